@@ -10,6 +10,8 @@ import (
 
 	"github.com/ekristen/azure-nuke/pkg/common"
 
+	_ "github.com/ekristen/azure-nuke/pkg/commands/config"
+	_ "github.com/ekristen/azure-nuke/pkg/commands/diff"
 	_ "github.com/ekristen/azure-nuke/pkg/commands/list"
 	_ "github.com/ekristen/azure-nuke/pkg/commands/run"
 
@@ -0,0 +1,20 @@
+package azure
+
+import (
+	"strings"
+)
+
+// typedARMTypes are the resource types that handle an ARM resource type by lower case ARM resource type
+var typedARMTypes = make(map[string]string)
+
+// RegisterARMType records that the ARM resource type, for example Microsoft.Compute/disks, is handled by the resource
+// type with the name. Generic listers skip these ARM resource types, so that they are not removed twice.
+func RegisterARMType(armType, name string) {
+	typedARMTypes[strings.ToLower(armType)] = name
+}
+
+// IsTypedARMType returns true if the ARM resource type is handled by a registered resource type
+func IsTypedARMType(armType string) bool {
+	_, ok := typedARMTypes[strings.ToLower(armType)]
+	return ok
+}
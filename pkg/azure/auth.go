@@ -2,10 +2,14 @@ package azure
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 
 	"github.com/hashicorp/go-azure-sdk/sdk/auth"
@@ -13,41 +17,97 @@ import (
 	"github.com/hashicorp/go-azure-sdk/sdk/environments"
 )
 
-func ConfigureAuth(
-	ctx context.Context,
-	environment, tenantID, clientID, clientSecret, clientCertFile, clientFedTokenFile string,
-) (*Authorizers, error) {
-	env, err := environments.FromName(environment)
+// AuthOptions are the options used to configure authentication against Azure
+type AuthOptions struct {
+	Environment        string
+	TenantID           string
+	ClientID           string
+	ClientSecret       string
+	ClientCertFile     string
+	ClientFedTokenFile string
+
+	// UseManagedIdentity authenticates using the system-assigned managed identity, or the user-assigned managed
+	// identity identified by ManagedIdentityClientID
+	UseManagedIdentity      bool
+	ManagedIdentityClientID string
+
+	// UseDefaultCredential authenticates using the chain of credentials of the DefaultAzureCredential, which tries
+	// the environment, workload identity, managed identity and the Azure CLI in that order
+	UseDefaultCredential bool
+
+	// EnvironmentMetadataURL is the ARM metadata endpoint the environment is loaded from instead of resolving it by
+	// name, e.g. for Azure Stack Hub
+	EnvironmentMetadataURL string
+}
+
+func ConfigureAuth(ctx context.Context, opts *AuthOptions) (*Authorizers, error) { //nolint:funlen,gocyclo
+	if opts.UseManagedIdentity && opts.UseDefaultCredential {
+		return nil, fmt.Errorf("managed identity and default credential authentication are mutually exclusive")
+	}
+
+	env, err := ResolveEnvironment(ctx, opts.Environment, opts.EnvironmentMetadataURL)
 	if err != nil {
 		return nil, err
 	}
 
+	cloudConfig, err := CloudConfiguration(env)
+	if err != nil {
+		return nil, err
+	}
+
+	logrus.Debugf("environment: %s (%s)", env.Name, cloudConfig.Services[cloud.ResourceManager].Endpoint)
+
+	// Note: the credentials and the clients of azcore have to use the cloud of the environment, otherwise they talk to
+	// the public cloud
+	clientOptions := azcore.ClientOptions{Cloud: cloudConfig}
+	disableInstanceDiscovery := usesADFS(env)
+
+	if opts.UseDefaultCredential || opts.UseManagedIdentity {
+		var authorizers *Authorizers
+		if opts.UseManagedIdentity {
+			authorizers, err = configureManagedIdentityAuth(env, opts, clientOptions)
+		} else {
+			authorizers, err = configureDefaultCredentialAuth(env, opts, clientOptions, disableInstanceDiscovery)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		authorizers.configureEnvironment(env, clientOptions)
+
+		return authorizers, nil
+	}
+
 	authorizers := &Authorizers{}
 
 	credentials := auth.Credentials{
 		Environment: *env,
-		TenantID:    tenantID,
-		ClientID:    clientID,
+		TenantID:    opts.TenantID,
+		ClientID:    opts.ClientID,
 
 		EnableAuthenticatingUsingClientSecret: true,
 	}
 
-	if clientSecret != "" {
+	if opts.ClientSecret != "" {
 		logrus.Debug("authentication type: client secret")
 		credentials.EnableAuthenticatingUsingClientSecret = true
-		credentials.ClientSecret = clientSecret
+		credentials.ClientSecret = opts.ClientSecret
 
-		creds, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, &azidentity.ClientSecretCredentialOptions{})
+		creds, err := azidentity.NewClientSecretCredential(
+			opts.TenantID, opts.ClientID, opts.ClientSecret, &azidentity.ClientSecretCredentialOptions{
+				ClientOptions:            clientOptions,
+				DisableInstanceDiscovery: disableInstanceDiscovery,
+			})
 		if err != nil {
 			return nil, err
 		}
 		authorizers.IdentityCreds = creds
-	} else if clientCertFile != "" {
+	} else if opts.ClientCertFile != "" {
 		logrus.Debug("authentication type: client certificate")
 		credentials.EnableAuthenticatingUsingClientCertificate = true
-		credentials.ClientCertificatePath = clientCertFile
+		credentials.ClientCertificatePath = opts.ClientCertFile
 
-		certData, err := os.ReadFile(clientCertFile)
+		certData, err := os.ReadFile(opts.ClientCertFile)
 		if err != nil {
 			return nil, err
 		}
@@ -57,14 +117,18 @@ func ConfigureAuth(
 			return nil, err
 		}
 
-		creds, err := azidentity.NewClientCertificateCredential(tenantID, clientID, certs, pkey, &azidentity.ClientCertificateCredentialOptions{})
+		creds, err := azidentity.NewClientCertificateCredential(
+			opts.TenantID, opts.ClientID, certs, pkey, &azidentity.ClientCertificateCredentialOptions{
+				ClientOptions:            clientOptions,
+				DisableInstanceDiscovery: disableInstanceDiscovery,
+			})
 		if err != nil {
 			return nil, err
 		}
 		authorizers.IdentityCreds = creds
-	} else if clientFedTokenFile != "" {
+	} else if opts.ClientFedTokenFile != "" {
 		logrus.Debug("authentication type: federated token")
-		token, err := os.ReadFile(clientFedTokenFile)
+		token, err := os.ReadFile(opts.ClientFedTokenFile)
 		if err != nil {
 			return nil, err
 		}
@@ -72,9 +136,11 @@ func ConfigureAuth(
 		credentials.OIDCAssertionToken = string(token)
 
 		creds, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
-			ClientID:      clientID,
-			TenantID:      tenantID,
-			TokenFilePath: clientFedTokenFile,
+			ClientOptions:            clientOptions,
+			ClientID:                 opts.ClientID,
+			TenantID:                 opts.TenantID,
+			TokenFilePath:            opts.ClientFedTokenFile,
+			DisableInstanceDiscovery: disableInstanceDiscovery,
 		})
 		if err != nil {
 			return nil, err
@@ -85,7 +151,7 @@ func ConfigureAuth(
 		credentials.EnableAuthenticatingUsingAzureCLI = true
 
 		creds, err := azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{
-			TenantID: tenantID,
+			TenantID: opts.TenantID,
 		})
 		if err != nil {
 			return nil, err
@@ -103,11 +169,81 @@ func ConfigureAuth(
 		return nil, err
 	}
 
-	authorizers.Management = autorest.AutorestAuthorizer(mgmtAuthorizer)
-	authorizers.Graph = autorest.AutorestAuthorizer(graphAuthorizer)
+	authorizers.setAuthorizers(graphAuthorizer, mgmtAuthorizer)
+	authorizers.configureEnvironment(env, clientOptions)
+
+	return authorizers, nil
+}
+
+// configureDefaultCredentialAuth configures the authorizers using the DefaultAzureCredential, the hashicorp
+// authorizers have no equivalent of the chain, so they are backed by the same credential instead.
+func configureDefaultCredentialAuth(env *environments.Environment, opts *AuthOptions,
+	clientOptions azcore.ClientOptions, disableInstanceDiscovery bool) (*Authorizers, error) {
+	logrus.Debug("authentication type: default credential")
+
+	creds, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions:            clientOptions,
+		TenantID:                 opts.TenantID,
+		DisableInstanceDiscovery: disableInstanceDiscovery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newTokenCredentialAuthorizers(env, creds)
+}
+
+// configureManagedIdentityAuth configures the authorizers using the managed identity of the host. The hashicorp
+// authorizers only support the instance metadata service of VMs, the identity endpoint of Container Instances and App
+// Service is only supported by azidentity, so they are backed by the same credential instead.
+func configureManagedIdentityAuth(env *environments.Environment, opts *AuthOptions,
+	clientOptions azcore.ClientOptions) (*Authorizers, error) {
+	logrus.Debug("authentication type: managed identity")
 
-	authorizers.MicrosoftGraph = graphAuthorizer
-	authorizers.ResourceManager = mgmtAuthorizer
+	miOpts := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: clientOptions}
+	if opts.ManagedIdentityClientID != "" {
+		miOpts.ID = azidentity.ClientID(opts.ManagedIdentityClientID)
+	}
+
+	creds, err := azidentity.NewManagedIdentityCredential(miOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTokenCredentialAuthorizers(env, creds)
+}
+
+// newTokenCredentialAuthorizers creates the authorizers of all clients from a single azcore credential
+func newTokenCredentialAuthorizers(env *environments.Environment, creds azcore.TokenCredential) (*Authorizers, error) {
+	graphAuthorizer, err := NewTokenCredentialAuthorizer(creds, env.MicrosoftGraph)
+	if err != nil {
+		return nil, err
+	}
+
+	mgmtAuthorizer, err := NewTokenCredentialAuthorizer(creds, env.ResourceManager)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizers := &Authorizers{
+		IdentityCreds: creds,
+	}
+	authorizers.setAuthorizers(graphAuthorizer, mgmtAuthorizer)
 
 	return authorizers, nil
 }
+
+// configureEnvironment makes every ARM and Microsoft Graph client talk to the endpoints of the environment
+func (a *Authorizers) configureEnvironment(env *environments.Environment, clientOptions azcore.ClientOptions) {
+	a.ClientOptions = &arm.ClientOptions{ClientOptions: clientOptions}
+	a.GraphEndpoint = GraphEndpoint(env)
+}
+
+// setAuthorizers sets the hashicorp authorizers and their autorest equivalents
+func (a *Authorizers) setAuthorizers(graphAuthorizer, mgmtAuthorizer auth.Authorizer) {
+	a.Management = autorest.AutorestAuthorizer(mgmtAuthorizer)
+	a.Graph = autorest.AutorestAuthorizer(graphAuthorizer)
+
+	a.MicrosoftGraph = graphAuthorizer
+	a.ResourceManager = mgmtAuthorizer
+}
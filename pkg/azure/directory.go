@@ -0,0 +1,181 @@
+package azure
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/gotidy/ptr"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/odata"
+	"github.com/manicminer/hamilton/msgraph"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+)
+
+// getByIDsLimit is the maximum number of IDs that can be looked up with a single request to getByIds
+const getByIDsLimit = 1000
+
+const (
+	PrincipalTypeUser             = "user"
+	PrincipalTypeGroup            = "group"
+	PrincipalTypeServicePrincipal = "service_principal"
+	PrincipalTypeUnknown          = "unknown"
+)
+
+// Principal is an object of the directory that roles can be assigned to
+type Principal struct {
+	ID   string
+	Name string
+	Type string
+
+	// Exists is false if the principal was not found in the directory
+	Exists bool
+}
+
+// DirectoryResolver resolves the names of principals and role definitions. The results are cached by ID for the
+// whole run, so that they are shared by the scanners of all subscriptions.
+type DirectoryResolver struct {
+	authorizers *Authorizers
+
+	mu         sync.Mutex
+	principals map[string]*Principal
+	roleNames  map[string]*string
+}
+
+// NewDirectoryResolver creates a new directory resolver with an empty cache
+func NewDirectoryResolver(authorizers *Authorizers) *DirectoryResolver {
+	return &DirectoryResolver{
+		authorizers: authorizers,
+		principals:  make(map[string]*Principal),
+		roleNames:   make(map[string]*string),
+	}
+}
+
+// Principals returns the principals with the given IDs by ID. The IDs that are not cached yet are looked up in
+// batches, principals that do not exist in the directory are returned with the unknown type.
+func (d *DirectoryResolver) Principals(ctx context.Context, ids []string) (map[string]*Principal, error) {
+	resolved := make(map[string]*Principal, len(ids))
+	var missing []string
+
+	d.mu.Lock()
+	for _, id := range ids {
+		if id == "" {
+			resolved[id] = unknownPrincipal(id)
+		} else if p, ok := d.principals[strings.ToLower(id)]; ok {
+			resolved[id] = p
+		} else if _, ok := resolved[id]; !ok {
+			resolved[id] = nil
+			missing = append(missing, id)
+		}
+	}
+	d.mu.Unlock()
+
+	if len(missing) == 0 {
+		return resolved, nil
+	}
+
+	client := msgraph.NewDirectoryObjectsClient()
+	client.BaseClient.Authorizer = d.authorizers.MicrosoftGraph
+	client.BaseClient.DisableRetries = true
+	d.authorizers.ConfigureGraphClient(&client.BaseClient)
+
+	for start := 0; start < len(missing); start += getByIDsLimit {
+		batch := missing[start:min(start+getByIDsLimit, len(missing))]
+
+		objects, _, err := client.GetByIds(ctx, batch, []odata.ShortType{
+			odata.ShortTypeUser, odata.ShortTypeGroup, odata.ShortTypeServicePrincipal,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		found := make(map[string]*Principal)
+		if objects != nil {
+			for i := range *objects {
+				p := newPrincipal(&(*objects)[i])
+				found[strings.ToLower(p.ID)] = p
+			}
+		}
+
+		d.mu.Lock()
+		for _, id := range batch {
+			p, ok := found[strings.ToLower(id)]
+			if !ok {
+				// Note: getByIds omits the IDs that do not exist, those are cached as well so they are not looked up
+				// again by other subscriptions
+				p = unknownPrincipal(id)
+			}
+
+			d.principals[strings.ToLower(id)] = p
+			resolved[id] = p
+		}
+		d.mu.Unlock()
+	}
+
+	return resolved, nil
+}
+
+// RoleName returns the name of the role definition with the given ID. Role definitions are cached by their GUID,
+// because the ID of the same role definition differs by the subscription it is referenced from.
+func (d *DirectoryResolver) RoleName(ctx context.Context, roleDefinitionID string) (*string, error) {
+	key := strings.ToLower(RoleDefinitionGUID(roleDefinitionID))
+
+	d.mu.Lock()
+	name, ok := d.roleNames[key]
+	d.mu.Unlock()
+
+	if ok {
+		return name, nil
+	}
+
+	client, err := armauthorization.NewRoleDefinitionsClient(d.authorizers.IdentityCreds, d.authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := client.GetByID(ctx, roleDefinitionID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if def.Properties != nil {
+		name = def.Properties.RoleName
+	}
+
+	d.mu.Lock()
+	d.roleNames[key] = name
+	d.mu.Unlock()
+
+	return name, nil
+}
+
+func unknownPrincipal(id string) *Principal {
+	return &Principal{ID: id, Name: PrincipalTypeUnknown, Type: PrincipalTypeUnknown}
+}
+
+func newPrincipal(o *msgraph.DirectoryObject) *Principal {
+	p := &Principal{
+		ID:     ptr.ToString(o.ID()),
+		Name:   ptr.ToString(o.DisplayName),
+		Type:   PrincipalTypeUnknown,
+		Exists: true,
+	}
+
+	switch ptr.ToString(o.ODataType) {
+	case odata.TypeUser:
+		p.Type = PrincipalTypeUser
+	case odata.TypeGroup:
+		p.Type = PrincipalTypeGroup
+	case odata.TypeServicePrincipal:
+		p.Type = PrincipalTypeServicePrincipal
+	}
+
+	return p
+}
+
+// RoleDefinitionGUID returns the last segment of the ID of a role definition
+func RoleDefinitionGUID(roleDefinitionID string) string {
+	parts := strings.Split(roleDefinitionID, "/")
+	return parts[len(parts)-1]
+}
@@ -0,0 +1,76 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+const (
+	userPrincipalID    = "11111111-1111-1111-1111-111111111111"
+	spPrincipalID      = "22222222-2222-2222-2222-222222222222"
+	deletedPrincipalID = "33333333-3333-3333-3333-333333333333"
+)
+
+func TestDirectoryResolverPrincipals(t *testing.T) {
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodPost, "/v1.0/directoryObjects/getByIds", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{
+			{"@odata.type": "#microsoft.graph.user", "id": userPrincipalID, "displayName": "Test User"},
+			{"@odata.type": "#microsoft.graph.servicePrincipal", "id": spPrincipalID, "displayName": "pipeline"},
+		},
+	})
+
+	directory := azure.NewDirectoryResolver(server.Authorizers())
+
+	principals, err := directory.Principals(context.TODO(),
+		[]string{userPrincipalID, spPrincipalID, deletedPrincipalID, userPrincipalID})
+	require.NoError(t, err)
+	require.Len(t, principals, 3)
+
+	assert.Equal(t, &azure.Principal{
+		ID: userPrincipalID, Name: "Test User", Type: azure.PrincipalTypeUser, Exists: true,
+	}, principals[userPrincipalID])
+	assert.Equal(t, azure.PrincipalTypeServicePrincipal, principals[spPrincipalID].Type)
+	assert.Equal(t, azure.PrincipalTypeUnknown, principals[deletedPrincipalID].Type)
+	assert.False(t, principals[deletedPrincipalID].Exists)
+
+	// Note: the principals are cached, including the one that does not exist
+	principals, err = directory.Principals(context.TODO(), []string{spPrincipalID, deletedPrincipalID})
+	require.NoError(t, err)
+	assert.Equal(t, "pipeline", principals[spPrincipalID].Name)
+
+	requests := server.Requests(http.MethodPost, "/v1.0/directoryObjects/getByIds")
+	require.Len(t, requests, 1)
+	assert.Contains(t, string(requests[0].Body), deletedPrincipalID)
+}
+
+func TestDirectoryResolverRoleName(t *testing.T) {
+	const roleDefinitionPath = "/subscriptions/*/providers/Microsoft.Authorization/roleDefinitions/" +
+		"acdd72a7-3385-48ef-bd42-f606fba81ae7"
+
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, roleDefinitionPath, http.StatusOK, map[string]interface{}{
+		"id":         "/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7",
+		"properties": map[string]string{"roleName": "Reader"},
+	})
+
+	directory := azure.NewDirectoryResolver(server.Authorizers())
+
+	// Note: the same role definition is referenced with the ID of each subscription
+	for _, subscriptionID := range []string{azuretest.SubscriptionID, "00000000-0000-0000-0000-000000000003"} {
+		name, err := directory.RoleName(context.TODO(), "/subscriptions/"+subscriptionID+
+			"/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7")
+		require.NoError(t, err)
+		assert.Equal(t, ptr.String("Reader"), name)
+	}
+
+	assert.Len(t, server.Requests(http.MethodGet, roleDefinitionPath), 1)
+}
@@ -0,0 +1,81 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/environments"
+)
+
+// ResolveEnvironment returns the Azure environment with the given name. If a metadata URL is given, the environment is
+// loaded from that ARM metadata endpoint instead, which is required for custom clouds such as Azure Stack Hub, the name
+// then selects the environment of the endpoint.
+func ResolveEnvironment(ctx context.Context, name, metadataURL string) (*environments.Environment, error) {
+	if metadataURL == "" {
+		return environments.FromName(name)
+	}
+
+	env, err := environments.FromEndpoint(ctx, strings.TrimSuffix(metadataURL, "/"), name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the environment from %s: %w", metadataURL, err)
+	}
+
+	return env, nil
+}
+
+// CloudConfiguration returns the cloud configuration of the environment, it is used by the credentials and every
+// client of azcore to authenticate against and talk to the Resource Manager of the environment.
+func CloudConfiguration(env *environments.Environment) (cloud.Configuration, error) {
+	if env.Authorization == nil || env.Authorization.LoginEndpoint == "" {
+		return cloud.Configuration{}, fmt.Errorf("the environment %s has no login endpoint", env.Name)
+	}
+
+	if env.ResourceManager == nil {
+		return cloud.Configuration{}, fmt.Errorf("the environment %s has no resource manager endpoint", env.Name)
+	}
+
+	endpoint, ok := env.ResourceManager.Endpoint()
+	if !ok {
+		return cloud.Configuration{}, fmt.Errorf("the environment %s has no resource manager endpoint", env.Name)
+	}
+
+	audience := *endpoint
+	if identifier, ok := env.ResourceManager.ResourceIdentifier(); ok {
+		audience = *identifier
+	} else if len(env.Authorization.Audiences) > 0 {
+		audience = env.Authorization.Audiences[0]
+	}
+
+	return cloud.Configuration{
+		ActiveDirectoryAuthorityHost: strings.TrimSuffix(env.Authorization.LoginEndpoint, "/") + "/",
+		Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+			cloud.ResourceManager: {
+				Audience: audience,
+				Endpoint: *endpoint,
+			},
+		},
+	}, nil
+}
+
+// GraphEndpoint returns the Microsoft Graph endpoint of the environment, or an empty string if it has none
+func GraphEndpoint(env *environments.Environment) string {
+	if env.MicrosoftGraph == nil {
+		return ""
+	}
+
+	endpoint, ok := env.MicrosoftGraph.Endpoint()
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSuffix(*endpoint, "/")
+}
+
+// usesADFS returns true if the environment authenticates against Active Directory Federation Services, as an Azure
+// Stack Hub that is disconnected from Entra ID does. The authority of ADFS cannot be validated by instance discovery.
+func usesADFS(env *environments.Environment) bool {
+	return env.Authorization != nil && strings.EqualFold(env.Authorization.IdentityProvider, "ADFS")
+}
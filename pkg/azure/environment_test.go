@@ -0,0 +1,91 @@
+package azure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func metadataServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metadata/endpoints" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":                     "AzureStack",
+			"resourceManager":          "https://management.local.azurestack.external",
+			"microsoftGraphResourceId": "https://graph.local.azurestack.external/",
+			"authentication": map[string]interface{}{
+				"loginEndpoint":    "https://adfs.local.azurestack.external/adfs",
+				"audiences":        []string{"https://management.adfs.azurestack.local/0000"},
+				"tenant":           "adfs",
+				"identityProvider": "ADFS",
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestCloudConfigurationFromName(t *testing.T) {
+	env, err := azure.ResolveEnvironment(context.TODO(), "usgovernment", "")
+	require.NoError(t, err)
+
+	config, err := azure.CloudConfiguration(env)
+	require.NoError(t, err)
+	assert.Equal(t, "https://login.microsoftonline.us/", config.ActiveDirectoryAuthorityHost)
+	assert.Equal(t, cloud.ServiceConfiguration{
+		Audience: "https://management.usgovcloudapi.net",
+		Endpoint: "https://management.usgovcloudapi.net",
+	}, config.Services[cloud.ResourceManager])
+	assert.Equal(t, "https://graph.microsoft.us", azure.GraphEndpoint(env))
+
+	_, err = azure.ResolveEnvironment(context.TODO(), "unknown", "")
+	assert.EqualError(t, err, `no environment was found with the name "unknown"`)
+}
+
+func TestCloudConfigurationFromMetadata(t *testing.T) {
+	server := metadataServer(t)
+
+	env, err := azure.ResolveEnvironment(context.TODO(), "AzureStack", server.URL+"/")
+	require.NoError(t, err)
+
+	config, err := azure.CloudConfiguration(env)
+	require.NoError(t, err)
+	assert.Equal(t, "https://adfs.local.azurestack.external/adfs/", config.ActiveDirectoryAuthorityHost)
+	assert.Equal(t, "https://management.local.azurestack.external",
+		config.Services[cloud.ResourceManager].Endpoint)
+	assert.Equal(t, "https://graph.local.azurestack.external", azure.GraphEndpoint(env))
+}
+
+func TestConfigureAuthUsesEnvironment(t *testing.T) {
+	server := metadataServer(t)
+
+	authorizers, err := azure.ConfigureAuth(context.TODO(), &azure.AuthOptions{
+		Environment:            "AzureStack",
+		EnvironmentMetadataURL: server.URL,
+		TenantID:               azuretest.TenantID,
+		ClientID:               "00000000-0000-0000-0000-0000000000c1",
+		ClientSecret:           "secret",
+	})
+	require.NoError(t, err)
+
+	clientOptions := authorizers.ARMClientOptions()
+	assert.Equal(t, "https://management.local.azurestack.external",
+		clientOptions.Cloud.Services[cloud.ResourceManager].Endpoint)
+	assert.Equal(t, "https://graph.local.azurestack.external", authorizers.GraphEndpoint)
+}
@@ -0,0 +1,139 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// TenantOwner is used in place of a subscription for the resources that do not belong to a subscription
+const TenantOwner = "tenant"
+
+// RemovalLimits are the safeguards that abort a run before anything is removed when more resources would be removed
+// than expected, e.g. because of a filter that does not match anymore. A limit of zero is disabled.
+type RemovalLimits struct {
+	// MaxRemovals is the maximum number of resources that may be removed
+	MaxRemovals int
+
+	// MaxRemovalPercent is the maximum percentage of the scanned resources that may be removed
+	MaxRemovalPercent float64
+
+	// MaxRemovalsPerSubscription is the maximum number of resources that may be removed from a single subscription
+	MaxRemovalsPerSubscription int
+}
+
+// Check returns an error with a summary of the resources to remove per type when any of the limits is exceeded
+func (l *RemovalLimits) Check(q *queue.Queue) error {
+	items := RemovableItems(q)
+
+	if l.MaxRemovals > 0 && len(items) > l.MaxRemovals {
+		return limitError(fmt.Sprintf("refusing to remove %d resources, more than the maximum of %d (--max-removals)",
+			len(items), l.MaxRemovals), items)
+	}
+
+	if l.MaxRemovalPercent > 0 && q.Total() > 0 {
+		percent := float64(len(items)) / float64(q.Total()) * 100
+		if percent > l.MaxRemovalPercent {
+			return limitError(fmt.Sprintf(
+				"refusing to remove %d of %d resources (%.1f%%), more than the maximum of %g%% (--max-removal-percent)",
+				len(items), q.Total(), percent, l.MaxRemovalPercent), items)
+		}
+	}
+
+	if l.MaxRemovalsPerSubscription > 0 {
+		bySubscription := make(map[string][]*queue.Item)
+		for _, item := range items {
+			subscriptionID := SubscriptionOf(item)
+			if subscriptionID == TenantOwner {
+				continue
+			}
+
+			bySubscription[subscriptionID] = append(bySubscription[subscriptionID], item)
+		}
+
+		subscriptionIDs := make([]string, 0, len(bySubscription))
+		for subscriptionID := range bySubscription {
+			subscriptionIDs = append(subscriptionIDs, subscriptionID)
+		}
+		sort.Strings(subscriptionIDs)
+
+		for _, subscriptionID := range subscriptionIDs {
+			subscriptionItems := bySubscription[subscriptionID]
+			if len(subscriptionItems) > l.MaxRemovalsPerSubscription {
+				return limitError(fmt.Sprintf(
+					"refusing to remove %d resources from subscription %s, more than the maximum of %d "+
+						"(max-removals-per-subscription)",
+					len(subscriptionItems), subscriptionID, l.MaxRemovalsPerSubscription), subscriptionItems)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RemovableItems returns the items of the queue that are about to be removed
+func RemovableItems(q *queue.Queue) []*queue.Item {
+	items := make([]*queue.Item, 0)
+	if q == nil {
+		return items
+	}
+
+	for _, item := range q.GetItems() {
+		if state := item.GetState(); state == queue.ItemStateNew || state == queue.ItemStateNewDependency {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// SubscriptionOf returns the subscription of the item, or TenantOwner if it does not belong to a subscription
+func SubscriptionOf(item *queue.Item) string {
+	subscriptionID, _ := item.GetProperty("SubscriptionID")
+	if subscriptionID == "" {
+		return TenantOwner
+	}
+
+	return subscriptionID
+}
+
+// CountBySubscription returns the number of items per subscription, see SubscriptionOf
+func CountBySubscription(items []*queue.Item) map[string]int {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[SubscriptionOf(item)]++
+	}
+
+	return counts
+}
+
+// limitError returns the error of an exceeded limit, with the number of resources per type sorted by the count
+func limitError(message string, items []*queue.Item) error {
+	counts := make(map[string]int)
+	for _, item := range items {
+		counts[item.Type]++
+	}
+
+	resourceTypes := make([]string, 0, len(counts))
+	for resourceType := range counts {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Slice(resourceTypes, func(a, b int) bool {
+		if counts[resourceTypes[a]] != counts[resourceTypes[b]] {
+			return counts[resourceTypes[a]] > counts[resourceTypes[b]]
+		}
+
+		return resourceTypes[a] < resourceTypes[b]
+	})
+
+	var sb strings.Builder
+	sb.WriteString(message)
+	for _, resourceType := range resourceTypes {
+		fmt.Fprintf(&sb, "\n  %s: %d", resourceType, counts[resourceType])
+	}
+
+	return errors.New(sb.String())
+}
@@ -0,0 +1,78 @@
+package azure_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+const otherSubscriptionID = "00000000-0000-0000-0000-000000000004"
+
+func limitsQueue() *queue.Queue {
+	q := queue.New()
+
+	add := func(resourceType, subscriptionID string, state queue.ItemState, count int) {
+		for i := 0; i < count; i++ {
+			q.Items = append(q.Items, &queue.Item{
+				Type:  resourceType,
+				State: state,
+				Resource: &protectTestResource{
+					properties: types.NewProperties().Set("SubscriptionID", subscriptionID),
+				},
+			})
+		}
+	}
+
+	add("VirtualMachine", azuretest.SubscriptionID, queue.ItemStateNew, 3)
+	add("Disk", azuretest.SubscriptionID, queue.ItemStateNew, 4)
+	add("Disk", otherSubscriptionID, queue.ItemStateNewDependency, 1)
+	add("ServicePrincipal", "", queue.ItemStateNew, 2)
+	add("ResourceGroup", otherSubscriptionID, queue.ItemStateFiltered, 10)
+
+	return q
+}
+
+func TestRemovalLimits(t *testing.T) {
+	q := limitsQueue()
+
+	assert.NoError(t, (&azure.RemovalLimits{}).Check(q))
+	assert.NoError(t, (&azure.RemovalLimits{
+		MaxRemovals:                10,
+		MaxRemovalPercent:          50,
+		MaxRemovalsPerSubscription: 7,
+	}).Check(q))
+
+	assert.EqualError(t, (&azure.RemovalLimits{MaxRemovals: 9}).Check(q),
+		"refusing to remove 10 resources, more than the maximum of 9 (--max-removals)\n"+
+			"  Disk: 5\n"+
+			"  VirtualMachine: 3\n"+
+			"  ServicePrincipal: 2")
+
+	assert.EqualError(t, (&azure.RemovalLimits{MaxRemovalPercent: 40}).Check(q),
+		"refusing to remove 10 of 20 resources (50.0%), more than the maximum of 40% (--max-removal-percent)\n"+
+			"  Disk: 5\n"+
+			"  VirtualMachine: 3\n"+
+			"  ServicePrincipal: 2")
+
+	assert.EqualError(t, (&azure.RemovalLimits{MaxRemovalsPerSubscription: 6}).Check(q),
+		"refusing to remove 7 resources from subscription "+azuretest.SubscriptionID+
+			", more than the maximum of 6 (max-removals-per-subscription)\n"+
+			"  Disk: 4\n"+
+			"  VirtualMachine: 3")
+}
+
+func TestCountBySubscription(t *testing.T) {
+	assert.Equal(t, map[string]int{
+		azuretest.SubscriptionID: 7,
+		otherSubscriptionID:      1,
+		azure.TenantOwner:        2,
+	}, azure.CountBySubscription(azure.RemovableItems(limitsQueue())))
+
+	assert.Empty(t, azure.RemovableItems(nil))
+}
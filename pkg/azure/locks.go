@@ -0,0 +1,401 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+)
+
+var _ policy.Policy = &LockReleaser{}
+
+// lockPathSegment separates the scope of a management lock from its name in the ID of the lock
+const lockPathSegment = "/providers/microsoft.authorization/locks/"
+
+// Lock is a management lock that blocks the removal of a resource
+type Lock struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	Scope string `json:"scope"`
+
+	// properties are the properties of the lock as listed, they are used to restore the lock
+	properties *armlocks.ManagementLockProperties
+}
+
+func (l *Lock) String() string {
+	return fmt.Sprintf("%s (%s) at %s", l.Name, l.Level, l.Scope)
+}
+
+// LiftedLock is a management lock that was removed so that a resource could be removed
+type LiftedLock struct {
+	Lock
+	ResourceID string `json:"resource_id"`
+
+	// Restored is true if the lock was applied again after the removal of the resource, this is the case for the
+	// locks above the resource, which protect other resources as well
+	Restored bool `json:"restored"`
+}
+
+// ScopeLockedError is returned when the removal of a resource is blocked by management locks that are not removed
+type ScopeLockedError struct {
+	ResourceID string
+	Locks      []*Lock
+	Reason     string
+}
+
+func (e *ScopeLockedError) Error() string {
+	locks := make([]string, 0, len(e.Locks))
+	for _, l := range e.Locks {
+		locks = append(locks, l.String())
+	}
+
+	return fmt.Sprintf("removal is blocked by management lock(s) %s: %s", strings.Join(locks, ", "), e.Reason)
+}
+
+// LockScope returns the scope a management lock is applied to from the ID of the lock
+func LockScope(lockID string) string {
+	i := strings.LastIndex(strings.ToLower(lockID), lockPathSegment)
+	if i < 0 {
+		return ""
+	}
+
+	return lockID[:i]
+}
+
+// LockID returns the ID of the management lock with the given name that is applied to the scope
+func LockID(scope, name string) string {
+	return scope + "/providers/Microsoft.Authorization/locks/" + name
+}
+
+// LockScopeLevel returns the level of the scope a management lock is applied to, either Subscription, ResourceGroup
+// or Resource.
+func LockScopeLevel(scope string) string {
+	id, err := arm.ParseResourceID(scope)
+	if err != nil {
+		return ""
+	}
+
+	switch id.ResourceType.String() {
+	case arm.SubscriptionResourceType.String():
+		return "Subscription"
+	case arm.ResourceGroupResourceType.String():
+		return "ResourceGroup"
+	}
+
+	return "Resource"
+}
+
+// LockReleaser detects deletes of ARM resources that fail because the scope is locked. The management locks at the
+// level of the resource, its resource group and its subscription, as well as the locks on resources below it, are
+// looked up and, when enabled, removed before the delete is sent again. Locks that are protected are never removed.
+// The locks above the resource protect other resources as well, they are applied again once the delete was sent.
+type LockReleaser struct {
+	authorizers *Authorizers
+	remove      bool
+
+	// release serializes the deletes that lift locks, so that a lock above one resource is not restored while the
+	// delete of another resource relies on it being lifted
+	release sync.Mutex
+
+	mu        sync.Mutex
+	protected map[string]struct{}
+	lifted    []*LiftedLock
+}
+
+// NewLockReleaser creates a new lock releaser, locks are only removed if remove is true
+func NewLockReleaser(authorizers *Authorizers, remove bool) *LockReleaser {
+	return &LockReleaser{
+		authorizers: authorizers,
+		remove:      remove,
+		protected:   make(map[string]struct{}),
+	}
+}
+
+// Protect prevents the management locks with the given IDs from being removed
+func (l *LockReleaser) Protect(lockIDs ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range lockIDs {
+		l.protected[strings.ToLower(id)] = struct{}{}
+	}
+}
+
+// Lifted returns the management locks that were removed
+func (l *LockReleaser) Lifted() []*LiftedLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]*LiftedLock{}, l.lifted...)
+}
+
+// Do implements the policy.Policy interface of azcore
+func (l *LockReleaser) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	if raw.Method != http.MethodDelete {
+		return req.Next()
+	}
+
+	resp, err := req.Next()
+	if err != nil || !isScopeLocked(resp) {
+		return resp, err
+	}
+
+	ctx := raw.Context()
+	resourceID := raw.URL.Path
+
+	log := logrus.WithField("component", "lock-releaser").WithField("resource", resourceID)
+
+	locks, err := l.blockingLocks(ctx, resourceID)
+	if err != nil {
+		log.WithError(err).Warn("unable to look up the management locks blocking the removal")
+		return resp, nil
+	}
+
+	if len(locks) == 0 {
+		return resp, nil
+	}
+
+	if !l.remove {
+		return nil, &ScopeLockedError{
+			ResourceID: resourceID,
+			Locks:      locks,
+			Reason:     "enable --remove-blocking-locks to remove them",
+		}
+	}
+
+	if protected := l.protectedLocks(locks); len(protected) > 0 {
+		return nil, &ScopeLockedError{
+			ResourceID: resourceID,
+			Locks:      protected,
+			Reason:     "the locks are filtered by the configuration",
+		}
+	}
+
+	l.release.Lock()
+	defer l.release.Unlock()
+
+	lifted, err := l.lift(ctx, resourceID, locks)
+	if err == nil {
+		err = req.RewindBody()
+	}
+
+	var retried *http.Response
+	if err == nil {
+		retried, err = req.Clone(ctx).Next()
+	}
+
+	// Note: the locks above the resource are always restored, the other locks only if the resource was not removed
+	removed := err == nil && retried.StatusCode < http.StatusMultipleChoices
+	l.restore(ctx, resourceID, lifted, !removed)
+
+	return retried, err
+}
+
+// blockingLocks returns the management locks that apply to the resource, either at its own level, at a level
+// above or on a resource below it.
+func (l *LockReleaser) blockingLocks(ctx context.Context, resourceID string) ([]*Lock, error) {
+	id, err := arm.ParseResourceID(resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := l.client(id.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceID = strings.ToLower(resourceID)
+	locks := make([]*Lock, 0)
+
+	pager := client.NewListAtSubscriptionLevelPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lock := range page.Value {
+			scope := LockScope(ptr.ToString(lock.ID))
+			if !isWithinScope(resourceID, strings.ToLower(scope)) {
+				continue
+			}
+
+			var level string
+			if lock.Properties != nil && lock.Properties.Level != nil {
+				level = string(*lock.Properties.Level)
+			}
+
+			locks = append(locks, &Lock{
+				ID:         ptr.ToString(lock.ID),
+				Name:       ptr.ToString(lock.Name),
+				Level:      level,
+				Scope:      scope,
+				properties: lock.Properties,
+			})
+		}
+	}
+
+	return locks, nil
+}
+
+// lift removes the management locks and records them as lifted for the resource, the locks that were removed are
+// returned even if removing one of the locks failed
+func (l *LockReleaser) lift(ctx context.Context, resourceID string, locks []*Lock) ([]*LiftedLock, error) {
+	lifted := make([]*LiftedLock, 0, len(locks))
+	for _, lock := range locks {
+		client, err := l.scopeClient(lock.Scope)
+		if err != nil {
+			return lifted, err
+		}
+
+		if _, err := client.DeleteByScope(ctx, lock.Scope, lock.Name, nil); err != nil {
+			return lifted, fmt.Errorf("unable to remove management lock %s: %w", lock, err)
+		}
+
+		logrus.
+			WithField("component", "lock-releaser").
+			WithField("resource", resourceID).
+			Infof("removed management lock %s", lock)
+
+		liftedLock := &LiftedLock{
+			Lock:       *lock,
+			ResourceID: resourceID,
+		}
+		lifted = append(lifted, liftedLock)
+
+		l.mu.Lock()
+		l.lifted = append(l.lifted, liftedLock)
+		l.mu.Unlock()
+	}
+
+	return lifted, nil
+}
+
+// restore applies the lifted locks above the resource again, or all lifted locks if all is true. A lock that cannot
+// be restored is logged, the resource it was lifted for is not affected by it.
+func (l *LockReleaser) restore(ctx context.Context, resourceID string, lifted []*LiftedLock, all bool) {
+	log := logrus.WithField("component", "lock-releaser").WithField("resource", resourceID)
+
+	for _, lock := range lifted {
+		if !all && !isAboveScope(strings.ToLower(resourceID), strings.ToLower(lock.Scope)) {
+			continue
+		}
+
+		client, err := l.scopeClient(lock.Scope)
+		if err == nil {
+			_, err = client.CreateOrUpdateByScope(ctx, lock.Scope, lock.Name, armlocks.ManagementLockObject{
+				Properties: lock.restoreProperties(),
+			}, nil)
+		}
+		if err != nil {
+			log.WithError(err).Errorf("unable to restore management lock %s, it has to be applied again manually", lock)
+			continue
+		}
+
+		log.Infof("restored management lock %s", lock)
+
+		l.mu.Lock()
+		lock.Restored = true
+		l.mu.Unlock()
+	}
+}
+
+// restoreProperties returns the properties to apply the lock again with, the level is taken from the lock if the
+// properties were not listed
+func (l *Lock) restoreProperties() *armlocks.ManagementLockProperties {
+	properties := &armlocks.ManagementLockProperties{
+		Level: ptr.Of(armlocks.LockLevel(l.Level)),
+	}
+
+	if l.properties != nil {
+		properties.Notes = l.properties.Notes
+		properties.Owners = l.properties.Owners
+	}
+
+	return properties
+}
+
+// protectedLocks returns the locks that must not be removed
+func (l *LockReleaser) protectedLocks(locks []*Lock) []*Lock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	protected := make([]*Lock, 0)
+	for _, lock := range locks {
+		if _, ok := l.protected[strings.ToLower(lock.ID)]; ok {
+			protected = append(protected, lock)
+		}
+	}
+
+	return protected
+}
+
+// scopeClient creates the management locks client for the subscription of the scope
+func (l *LockReleaser) scopeClient(scope string) (*armlocks.ManagementLocksClient, error) {
+	id, err := arm.ParseResourceID(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.client(id.SubscriptionID)
+}
+
+// client creates the management locks client, without the lock releaser in its pipeline
+func (l *LockReleaser) client(subscriptionID string) (*armlocks.ManagementLocksClient, error) {
+	return armlocks.NewManagementLocksClient(subscriptionID, l.authorizers.IdentityCreds, l.authorizers.armClientOptions(false))
+}
+
+// isWithinScope returns true if the scope of a lock is the resource, one of its parents or one of its children
+func isWithinScope(resourceID, scope string) bool {
+	if scope == "" {
+		return false
+	}
+
+	return resourceID == scope ||
+		strings.HasPrefix(resourceID, scope+"/") ||
+		strings.HasPrefix(scope, resourceID+"/")
+}
+
+// isAboveScope returns true if the scope of a lock is one of the parents of the resource
+func isAboveScope(resourceID, scope string) bool {
+	return scope != "" && strings.HasPrefix(resourceID, scope+"/")
+}
+
+// isScopeLocked returns true if the response is the conflict that ARM returns for operations on a locked scope, the
+// body of the response is restored so that it can still be read by the caller.
+func isScopeLocked(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var payload struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return false
+	}
+
+	return strings.EqualFold(payload.Error.Code, "ScopeLocked")
+}
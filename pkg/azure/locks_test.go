@@ -0,0 +1,140 @@
+package azure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+const (
+	lockedResourceID = "/subscriptions/" + azuretest.SubscriptionID +
+		"/resourceGroups/rg-dev/providers/Microsoft.Network/virtualNetworks/vnet-dev"
+	resourceGroupLockID = "/subscriptions/" + azuretest.SubscriptionID +
+		"/resourceGroups/rg-dev/providers/Microsoft.Authorization/locks/rg-lock"
+)
+
+// newLockedServer returns a server where the first delete of the virtual network fails because of a lock on its
+// resource group, a lock on another resource group does not block the removal.
+func newLockedServer(t *testing.T) *azuretest.Server {
+	server := azuretest.NewServer(t)
+
+	deletes := 0
+	server.Handle(http.MethodDelete, lockedResourceID, func(w http.ResponseWriter, _ *http.Request) {
+		deletes++
+		if deletes == 1 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"code":"ScopeLocked","message":"the scope is locked"}}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/providers/Microsoft.Authorization/locks",
+		http.StatusOK, map[string]interface{}{
+			"value": []map[string]interface{}{
+				{
+					"id":         resourceGroupLockID,
+					"name":       "rg-lock",
+					"properties": map[string]string{"level": "CanNotDelete"},
+				},
+				{
+					"id": "/subscriptions/" + azuretest.SubscriptionID +
+						"/resourceGroups/rg-prod/providers/Microsoft.Authorization/locks/prod-lock",
+					"name":       "prod-lock",
+					"properties": map[string]string{"level": "ReadOnly"},
+				},
+			},
+		})
+	server.Respond(http.MethodDelete, resourceGroupLockID, http.StatusOK, nil)
+	server.Respond(http.MethodPut, resourceGroupLockID, http.StatusOK, map[string]interface{}{
+		"id": resourceGroupLockID, "name": "rg-lock", "properties": map[string]string{"level": "CanNotDelete"},
+	})
+
+	return server
+}
+
+func deleteLockedResource(t *testing.T, authorizers *azure.Authorizers) error {
+	client, err := armresources.NewClient(azuretest.SubscriptionID, authorizers.IdentityCreds, authorizers.ARMClientOptions())
+	require.NoError(t, err)
+
+	poller, err := client.BeginDeleteByID(context.TODO(), lockedResourceID, "2023-09-01", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(context.TODO(), nil)
+	return err
+}
+
+func TestLockReleaserRemovesBlockingLocks(t *testing.T) {
+	server := newLockedServer(t)
+
+	authorizers := server.Authorizers()
+	authorizers.LockReleaser = azure.NewLockReleaser(authorizers, true)
+
+	assert.NoError(t, deleteLockedResource(t, authorizers))
+	assert.Len(t, server.Requests(http.MethodDelete, lockedResourceID), 2)
+	assert.Len(t, server.Requests(http.MethodDelete, resourceGroupLockID), 1)
+
+	// the lock of the resource group protects other resources as well, it is applied again
+	restores := server.Requests(http.MethodPut, resourceGroupLockID)
+	require.Len(t, restores, 1)
+	assert.JSONEq(t, `{"properties":{"level":"CanNotDelete"}}`, string(restores[0].Body))
+
+	lifted := authorizers.LockReleaser.Lifted()
+	require.Len(t, lifted, 1)
+	assert.Equal(t, "rg-lock", lifted[0].Name)
+	assert.Equal(t, "CanNotDelete", lifted[0].Level)
+	assert.Equal(t, "/subscriptions/"+azuretest.SubscriptionID+"/resourceGroups/rg-dev", lifted[0].Scope)
+	assert.Equal(t, lockedResourceID, lifted[0].ResourceID)
+	assert.True(t, lifted[0].Restored)
+}
+
+func TestLockReleaserReportsBlockingLocks(t *testing.T) {
+	server := newLockedServer(t)
+
+	authorizers := server.Authorizers()
+	authorizers.LockReleaser = azure.NewLockReleaser(authorizers, false)
+
+	err := deleteLockedResource(t, authorizers)
+
+	var lockedErr *azure.ScopeLockedError
+	require.True(t, errors.As(err, &lockedErr))
+	require.Len(t, lockedErr.Locks, 1)
+	assert.Equal(t, "rg-lock", lockedErr.Locks[0].Name)
+	assert.Contains(t, err.Error(), "--remove-blocking-locks")
+	assert.Empty(t, server.Requests(http.MethodDelete, resourceGroupLockID))
+}
+
+func TestLockReleaserKeepsProtectedLocks(t *testing.T) {
+	server := newLockedServer(t)
+
+	authorizers := server.Authorizers()
+	authorizers.LockReleaser = azure.NewLockReleaser(authorizers, true)
+	authorizers.LockReleaser.Protect(azure.LockID("/subscriptions/"+azuretest.SubscriptionID+"/resourceGroups/rg-dev", "rg-lock"))
+
+	err := deleteLockedResource(t, authorizers)
+
+	var lockedErr *azure.ScopeLockedError
+	require.True(t, errors.As(err, &lockedErr))
+	assert.Empty(t, server.Requests(http.MethodDelete, resourceGroupLockID))
+	assert.Empty(t, authorizers.LockReleaser.Lifted())
+}
+
+func TestLockScope(t *testing.T) {
+	scope := azure.LockScope(lockedResourceID + "/providers/Microsoft.Authorization/locks/vnet-lock")
+	assert.Equal(t, lockedResourceID, scope)
+	assert.Equal(t, "Resource", azure.LockScopeLevel(scope))
+	assert.Equal(t, "ResourceGroup", azure.LockScopeLevel(azure.LockScope(resourceGroupLockID)))
+	assert.Equal(t, "Subscription", azure.LockScopeLevel("/subscriptions/"+azuretest.SubscriptionID))
+}
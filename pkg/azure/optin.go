@@ -0,0 +1,27 @@
+package azure
+
+import (
+	"slices"
+
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+var optInResourceTypes types.Collection
+
+// RegisterOptIn marks a resource type as opt-in. Opt-in resource types are only scanned when they are explicitly
+// included, either on the command line or in the configuration.
+func RegisterOptIn(name string) {
+	if !slices.Contains(optInResourceTypes, name) {
+		optInResourceTypes = append(optInResourceTypes, name)
+	}
+}
+
+// IsOptIn returns true if the resource type has to be explicitly included
+func IsOptIn(name string) bool {
+	return slices.Contains(optInResourceTypes, name)
+}
+
+// OptInResourceTypes returns all resource types that have to be explicitly included
+func OptInResourceTypes() types.Collection {
+	return slices.Clone(optInResourceTypes)
+}
@@ -1,11 +1,20 @@
 package azure
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"os"
+	"sort"
 	"time"
 
 	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/utils"
+
+	"github.com/ekristen/azure-nuke/pkg/report"
 )
 
 // Prompt is a struct that contains the parameters and tenant details use to craft a unique prompt
@@ -13,22 +22,146 @@ import (
 type Prompt struct {
 	Parameters *libnuke.Parameters
 	Tenant     *Tenant
+
+	// Queue is the queue of the scanned resources, if set the number of resources to remove is shown
+	Queue *queue.Queue
+
+	// ConfirmSubscriptionName requires the name of the subscription to be entered instead of the tenant ID when only
+	// one subscription is targeted
+	ConfirmSubscriptionName bool
+
+	// ApprovalToken approves the removal without prompting, it has to match the token of the resources to remove that
+	// is shown by a dry run
+	ApprovalToken string
+
+	// Output is where the prompt is written to, defaults to stdout
+	Output io.Writer
 }
 
 func (p *Prompt) Prompt() error {
 	forceSleep := time.Duration(p.Parameters.ForceSleep) * time.Second
 
-	fmt.Printf("Do you really want to nuke the tenant and subscriptions with "+
+	p.printf("Do you really want to nuke the tenant and subscriptions with "+
 		"the ID %s?\n", p.Tenant.ID)
-	if p.Parameters.Force {
-		fmt.Printf("Waiting %v before continuing.\n", forceSleep)
+	p.printSummary()
+
+	switch {
+	case p.ApprovalToken != "":
+		return p.approve()
+	case p.Parameters.Force:
+		p.printf("Waiting %v before continuing.\n", forceSleep)
 		time.Sleep(forceSleep)
-	} else {
-		fmt.Printf("Do you want to continue? Enter tenant ID to continue.\n")
-		if err := utils.Prompt(p.Tenant.ID); err != nil {
+	default:
+		expected, what := p.Tenant.ID, "tenant ID"
+		if subscriptionID, ok := p.singleSubscription(); ok && p.ConfirmSubscriptionName {
+			expected, what = p.Tenant.SubscriptionName(subscriptionID), "subscription name"
+		}
+
+		p.printf("Do you want to continue? Enter %s to continue.\n", what)
+		if err := utils.Prompt(expected); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// approve checks the approval token against the resources to remove. The token cannot be checked before the
+// resources have been scanned, the prompt before the scan is approved as long as a token is given.
+func (p *Prompt) approve() error {
+	if p.Queue == nil {
+		p.printf("Approval token given, it is checked against the resources to remove after the scan.\n")
+		return nil
+	}
+
+	token := ApprovalToken(p.Tenant.ID, RemovableItems(p.Queue))
+	if p.ApprovalToken != token {
+		return fmt.Errorf("the approval token does not match the resources to remove (expected %s), "+
+			"the resources changed since the token was issued", token)
+	}
+
+	p.printf("Approval token matches the resources to remove, continuing.\n")
+
+	return nil
+}
+
+// singleSubscription returns the subscription if exactly one subscription is targeted
+func (p *Prompt) singleSubscription() (string, bool) {
+	if len(p.Tenant.SubscriptionIds) != 1 {
+		return "", false
+	}
+
+	return p.Tenant.SubscriptionIds[0], true
+}
+
+// printSummary prints the targeted subscriptions and, once the resources have been scanned, the number of resources
+// that are about to be removed per subscription and per scope
+func (p *Prompt) printSummary() {
+	items := RemovableItems(p.Queue)
+	bySubscription := CountBySubscription(items)
+
+	p.printf("Subscriptions (%d):\n", len(p.Tenant.SubscriptionIds))
+	for _, subscriptionID := range p.Tenant.SubscriptionIds {
+		line := fmt.Sprintf("  %s", subscriptionID)
+		if name := p.Tenant.SubscriptionNames[subscriptionID]; name != "" {
+			line += fmt.Sprintf(" (%s)", name)
+		}
+
+		line += fmt.Sprintf(": %d resource groups", len(p.Tenant.ResourceGroups[subscriptionID]))
+		if p.Queue != nil {
+			line += fmt.Sprintf(", %d resources to remove", bySubscription[subscriptionID])
+		}
+
+		p.printf("%s\n", line)
+	}
+
+	if p.Queue == nil {
+		return
+	}
+
+	if count := bySubscription[TenantOwner]; count > 0 {
+		p.printf("  outside of subscriptions: %d resources to remove\n", count)
+	}
+
+	byScope := make(map[registry.Scope]int)
+	for _, item := range items {
+		if reg := registry.GetRegistration(item.Type); reg != nil {
+			byScope[reg.Scope]++
+		}
+	}
+
+	p.printf("Resources to remove (%d):\n", len(items))
+	for _, scope := range []registry.Scope{
+		TenantScope, ManagementGroupScope, SubscriptionScope, ResourceGroupScope,
+	} {
+		p.printf("  %s: %d\n", scope, byScope[scope])
+	}
+}
+
+func (p *Prompt) printf(format string, a ...any) {
+	out := p.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	_, _ = fmt.Fprintf(out, format, a...)
+}
+
+// ApprovalToken returns the token that approves the removal of exactly these resources from the tenant. A dry run
+// shows the token, passing it to the run that removes the resources skips the prompt.
+func ApprovalToken(tenantID string, items []*queue.Item) string {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, report.NewItem(item).Key())
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(tenantID))
+	for _, key := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
@@ -0,0 +1,118 @@
+package azure_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     "TestPromptType",
+		Scope:    azure.ResourceGroupScope,
+		Resource: &protectTestResource{},
+	})
+}
+
+func promptTenant() *azure.Tenant {
+	return &azure.Tenant{
+		ID:              azuretest.TenantID,
+		SubscriptionIds: []string{azuretest.SubscriptionID, otherSubscriptionID},
+		SubscriptionNames: map[string]string{
+			azuretest.SubscriptionID: "sandbox-dev",
+		},
+		ResourceGroups: map[string][]string{
+			azuretest.SubscriptionID: {"rg-east", "rg-west"},
+		},
+	}
+}
+
+func promptQueue() *queue.Queue {
+	q := queue.New()
+	for _, subscriptionID := range []string{azuretest.SubscriptionID, azuretest.SubscriptionID, ""} {
+		q.Items = append(q.Items, &queue.Item{
+			Type:  "TestPromptType",
+			State: queue.ItemStateNew,
+			Resource: &protectTestResource{
+				properties: types.NewProperties().Set("SubscriptionID", subscriptionID).Set("Name", subscriptionID),
+			},
+		})
+	}
+
+	return q
+}
+
+func TestPromptSummary(t *testing.T) {
+	var buf bytes.Buffer
+	prompt := &azure.Prompt{
+		Parameters: &libnuke.Parameters{Force: true},
+		Tenant:     promptTenant(),
+		Output:     &buf,
+	}
+
+	require.NoError(t, prompt.Prompt())
+	assert.Equal(t, "Do you really want to nuke the tenant and subscriptions with the ID "+azuretest.TenantID+"?\n"+
+		"Subscriptions (2):\n"+
+		"  "+azuretest.SubscriptionID+" (sandbox-dev): 2 resource groups\n"+
+		"  "+otherSubscriptionID+": 0 resource groups\n"+
+		"Waiting 0s before continuing.\n", buf.String())
+
+	buf.Reset()
+	prompt.Queue = promptQueue()
+
+	require.NoError(t, prompt.Prompt())
+	assert.Equal(t, "Do you really want to nuke the tenant and subscriptions with the ID "+azuretest.TenantID+"?\n"+
+		"Subscriptions (2):\n"+
+		"  "+azuretest.SubscriptionID+" (sandbox-dev): 2 resource groups, 2 resources to remove\n"+
+		"  "+otherSubscriptionID+": 0 resource groups, 0 resources to remove\n"+
+		"  outside of subscriptions: 1 resources to remove\n"+
+		"Resources to remove (3):\n"+
+		"  tenant: 0\n"+
+		"  management-group: 0\n"+
+		"  subscription: 0\n"+
+		"  resource-group: 3\n"+
+		"Waiting 0s before continuing.\n", buf.String())
+}
+
+func TestPromptApprovalToken(t *testing.T) {
+	q := promptQueue()
+	token := azure.ApprovalToken(azuretest.TenantID, azure.RemovableItems(q))
+	assert.Len(t, token, 16)
+
+	var buf bytes.Buffer
+	prompt := &azure.Prompt{
+		Parameters:    &libnuke.Parameters{},
+		Tenant:        promptTenant(),
+		ApprovalToken: token,
+		Output:        &buf,
+	}
+
+	// Note: before the scan the token cannot be checked yet
+	require.NoError(t, prompt.Prompt())
+	assert.Contains(t, buf.String(), "Approval token given, it is checked against the resources to remove after the scan.\n")
+
+	prompt.Queue = q
+	require.NoError(t, prompt.Prompt())
+	assert.Contains(t, buf.String(), "Approval token matches the resources to remove, continuing.\n")
+
+	q.Items[0].State = queue.ItemStateFiltered
+	assert.EqualError(t, prompt.Prompt(), "the approval token does not match the resources to remove (expected "+
+		azure.ApprovalToken(azuretest.TenantID, azure.RemovableItems(q))+
+		"), the resources changed since the token was issued")
+}
+
+func TestTenantSubscriptionName(t *testing.T) {
+	tenant := promptTenant()
+	assert.Equal(t, "sandbox-dev", tenant.SubscriptionName(azuretest.SubscriptionID))
+	assert.Equal(t, otherSubscriptionID, tenant.SubscriptionName(otherSubscriptionID))
+}
@@ -0,0 +1,137 @@
+package azure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// ProtectTag is a tag that protects the resources that carry it from being removed. When it is set on a resource group
+// or a subscription, everything within the resource group or subscription is protected as well.
+type ProtectTag struct {
+	Key string
+
+	// Value is the value the tag must have, if empty any non-empty value protects the resource
+	Value string
+}
+
+// ParseProtectTags parses the protect tags of the configuration, each is either `key` or `key=value`
+func ParseProtectTags(tags []string) ([]ProtectTag, error) {
+	parsed := make([]ProtectTag, 0, len(tags))
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		if strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid protect tag %q, the key cannot be empty", tag)
+		}
+
+		parsed = append(parsed, ProtectTag{Key: key, Value: value})
+	}
+
+	return parsed, nil
+}
+
+func (t ProtectTag) String() string {
+	if t.Value == "" {
+		return t.Key
+	}
+
+	return fmt.Sprintf("%s=%s", t.Key, t.Value)
+}
+
+// Matches returns true if the tags contain the protect tag
+func (t ProtectTag) Matches(tags map[string]string) bool {
+	value, ok := tags[t.Key]
+	if !ok || value == "" {
+		return false
+	}
+
+	return t.Value == "" || t.Value == value
+}
+
+// Filter returns the global filter that protects the resources that carry the tag themselves
+func (t ProtectTag) Filter() filter.Filter {
+	if t.Value == "" {
+		return filter.Filter{Property: "tag:" + t.Key, Type: filter.Exact, Value: "", Invert: true}
+	}
+
+	return filter.Filter{Property: "tag:" + t.Key, Type: filter.Exact, Value: t.Value}
+}
+
+// TagProtection protects the resources within resource groups and subscriptions that carry a protect tag. The tags of
+// the resources themselves are handled by the global filters of the protect tags.
+type TagProtection struct {
+	Tags []ProtectTag
+
+	// ResourceGroupTags are the tags of the resource groups by subscription id and lower case resource group name
+	ResourceGroupTags map[string]map[string]map[string]string
+
+	// SubscriptionTags are the tags of the subscriptions by subscription id
+	SubscriptionTags map[string]map[string]string
+}
+
+// NewTagProtection returns the protection for the resource groups and subscriptions of the tenant, it returns nil if
+// there are no protect tags.
+func NewTagProtection(tags []ProtectTag, tenant *Tenant) *TagProtection {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return &TagProtection{
+		Tags:              tags,
+		ResourceGroupTags: tenant.ResourceGroupTags,
+		SubscriptionTags:  tenant.SubscriptionTags,
+	}
+}
+
+// ProtectedBy returns why a resource in the subscription and resource group is protected, or an empty string if it is
+// not. The resource group takes precedence over the subscription.
+func (p *TagProtection) ProtectedBy(subscriptionID, resourceGroup string) string {
+	if p == nil || subscriptionID == "" {
+		return ""
+	}
+
+	if resourceGroup != "" {
+		groupTags := p.ResourceGroupTags[subscriptionID][strings.ToLower(resourceGroup)]
+		for _, tag := range p.Tags {
+			if tag.Matches(groupTags) {
+				return fmt.Sprintf("protected by tag %s (inherited from resource group %s)", tag, resourceGroup)
+			}
+		}
+	}
+
+	for _, tag := range p.Tags {
+		if tag.Matches(p.SubscriptionTags[subscriptionID]) {
+			return fmt.Sprintf("protected by tag %s (inherited from subscription %s)", tag, subscriptionID)
+		}
+	}
+
+	return ""
+}
+
+// Protect filters the item if its resource group or subscription is protected, it returns true if the item was
+// filtered. Items that are not about to be removed are left untouched.
+func (p *TagProtection) Protect(item *queue.Item) bool {
+	if p == nil {
+		return false
+	}
+
+	if state := item.GetState(); state != queue.ItemStateNew && state != queue.ItemStateNewDependency {
+		return false
+	}
+
+	// Note: resources that do not expose these properties are not within a subscription
+	subscriptionID, _ := item.GetProperty("SubscriptionID")
+	resourceGroup, _ := item.GetProperty("ResourceGroup")
+
+	reason := p.ProtectedBy(subscriptionID, resourceGroup)
+	if reason == "" {
+		return false
+	}
+
+	item.State = queue.ItemStateFiltered
+	item.Reason = reason
+
+	return true
+}
@@ -0,0 +1,157 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+type protectTestResource struct {
+	properties types.Properties
+}
+
+func (r *protectTestResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *protectTestResource) Properties() types.Properties {
+	return r.properties
+}
+
+func TestParseProtectTags(t *testing.T) {
+	tags, err := azure.ParseProtectTags([]string{"nuke-protect", "env=production", "note=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, []azure.ProtectTag{
+		{Key: "nuke-protect"},
+		{Key: "env", Value: "production"},
+		{Key: "note", Value: "a=b"},
+	}, tags)
+	assert.Equal(t, "env=production", tags[1].String())
+
+	_, err = azure.ParseProtectTags([]string{"=production"})
+	assert.EqualError(t, err, `invalid protect tag "=production", the key cannot be empty`)
+}
+
+func TestProtectTagFilter(t *testing.T) {
+	cases := []struct {
+		tag      azure.ProtectTag
+		value    string
+		expected bool
+	}{
+		{tag: azure.ProtectTag{Key: "nuke-protect"}, value: "true", expected: true},
+		{tag: azure.ProtectTag{Key: "nuke-protect"}, value: "", expected: false},
+		{tag: azure.ProtectTag{Key: "env", Value: "production"}, value: "production", expected: true},
+		{tag: azure.ProtectTag{Key: "env", Value: "production"}, value: "dev", expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.tag.String()+"/"+tc.value, func(t *testing.T) {
+			f := tc.tag.Filter()
+			assert.Equal(t, "tag:"+tc.tag.Key, f.Property)
+
+			match, err := f.Match(tc.value)
+			require.NoError(t, err)
+			if f.Invert {
+				match = !match
+			}
+
+			assert.Equal(t, tc.expected, match)
+			assert.Equal(t, tc.expected, tc.tag.Matches(map[string]string{tc.tag.Key: tc.value}))
+		})
+	}
+}
+
+func TestTagProtectionProtect(t *testing.T) {
+	protection := &azure.TagProtection{
+		Tags: []azure.ProtectTag{{Key: "nuke-protect"}, {Key: "env", Value: "production"}},
+		ResourceGroupTags: map[string]map[string]map[string]string{
+			azuretest.SubscriptionID: {
+				"rg-keep":  {"nuke-protect": "true"},
+				"rg-other": {"env": "dev"},
+			},
+		},
+		SubscriptionTags: map[string]map[string]string{
+			"00000000-0000-0000-0000-000000000004": {"env": "production"},
+		},
+	}
+
+	newItem := func(subscriptionID, resourceGroup string) *queue.Item {
+		return &queue.Item{
+			State: queue.ItemStateNew,
+			Resource: &protectTestResource{properties: types.NewProperties().
+				Set("SubscriptionID", subscriptionID).
+				Set("ResourceGroup", resourceGroup)},
+		}
+	}
+
+	item := newItem(azuretest.SubscriptionID, "RG-Keep")
+	assert.True(t, protection.Protect(item))
+	assert.Equal(t, queue.ItemStateFiltered, item.GetState())
+	assert.Equal(t, "protected by tag nuke-protect (inherited from resource group RG-Keep)", item.GetReason())
+
+	item = newItem("00000000-0000-0000-0000-000000000004", "rg-any")
+	assert.True(t, protection.Protect(item))
+	assert.Equal(t, "protected by tag env=production (inherited from subscription "+
+		"00000000-0000-0000-0000-000000000004)", item.GetReason())
+
+	item = newItem(azuretest.SubscriptionID, "rg-other")
+	assert.False(t, protection.Protect(item))
+	assert.Equal(t, queue.ItemStateNew, item.GetState())
+
+	item = newItem(azuretest.SubscriptionID, "rg-keep")
+	item.State = queue.ItemStateFinished
+	assert.False(t, protection.Protect(item))
+
+	var disabled *azure.TagProtection
+	assert.False(t, disabled.Protect(newItem(azuretest.SubscriptionID, "rg-keep")))
+}
+
+func TestNewTenantProtectTags(t *testing.T) {
+	server := subscriptionsServer(t)
+	for _, id := range []string{"00000000-0000-0000-0000-000000000004", "00000000-0000-0000-0000-000000000005"} {
+		server.Respond(http.MethodGet, "/subscriptions/"+id+"/resourcegroups", http.StatusOK,
+			map[string]interface{}{"value": []interface{}{}})
+	}
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups", http.StatusOK,
+		map[string]interface{}{
+			"value": []map[string]interface{}{
+				{"name": "RG-Keep", "location": "westus", "tags": map[string]string{"nuke-protect": "true"}},
+				{"name": "rg-east", "location": "eastus"},
+			},
+		})
+	server.Respond(http.MethodGet,
+		"/subscriptions/"+azuretest.SubscriptionID+"/providers/Microsoft.Resources/tags/default", http.StatusOK,
+		map[string]interface{}{"properties": map[string]interface{}{"tags": map[string]string{"env": "production"}}})
+	server.Respond(http.MethodGet,
+		"/subscriptions/00000000-0000-0000-0000-000000000004/providers/Microsoft.Resources/tags/default",
+		http.StatusForbidden, `{"error":{"code":"AuthorizationFailed","message":"denied"}}`)
+	server.Respond(http.MethodGet,
+		"/subscriptions/00000000-0000-0000-0000-000000000005/providers/Microsoft.Resources/tags/default",
+		http.StatusOK, map[string]interface{}{"properties": map[string]interface{}{"tags": map[string]string{}}})
+
+	tenant, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:               azuretest.TenantID,
+		Regions:                []string{"eastus"},
+		DiscoveryTimeout:       10 * time.Second,
+		LookupSubscriptionTags: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"rg-east"}, tenant.ResourceGroups[azuretest.SubscriptionID])
+	assert.Equal(t, map[string]map[string]map[string]string{
+		azuretest.SubscriptionID: {"rg-keep": {"nuke-protect": "true"}},
+	}, tenant.ResourceGroupTags)
+	assert.Equal(t, map[string]map[string]string{
+		azuretest.SubscriptionID: {"env": "production"},
+	}, tenant.SubscriptionTags)
+}
@@ -1,27 +1,59 @@
 package azure
 
 import (
+	"fmt"
 	"regexp"
+	"sync"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 )
 
 const (
-	TenantScope        registry.Scope = "tenant"
-	SubscriptionScope  registry.Scope = "subscription"
-	ResourceGroupScope registry.Scope = "resource-group"
+	TenantScope          registry.Scope = "tenant"
+	ManagementGroupScope registry.Scope = "management-group"
+	SubscriptionScope    registry.Scope = "subscription"
+	ResourceGroupScope   registry.Scope = "resource-group"
 )
 
 var ResourceGroupRegex = regexp.MustCompile(`/resourceGroups/([^/]+)`)
 
 type ListerOpts struct {
-	Authorizers    *Authorizers
-	TenantID       string
-	SubscriptionID string
-	ResourceGroup  string
-	ResourceGroups []string
-	Region         string
-	Regions        []string
+	Authorizers       *Authorizers
+	TenantID          string
+	ManagementGroupID string
+	SubscriptionID    string
+	ResourceGroup     string
+	ResourceGroups    []string
+	Region            string
+	Regions           []string
+
+	// SubscriptionIDs are all subscriptions that are targeted by the run
+	SubscriptionIDs []string
+
+	// TagProtection protects the resources within protected resource groups and subscriptions, it is applied to the
+	// resources before they are queued
+	TagProtection *TagProtection
+
+	// Directory resolves the names of principals and role definitions, it is shared by all scanners of the run
+	Directory *DirectoryResolver
+
+	// ResourceTimes caches the times tracked by Azure Resource Manager, it is shared by all scanners of the run
+	ResourceTimes *ResourceTimesCache
+
+	directoryOnce     sync.Once
+	resourceTimesOnce sync.Once
+}
+
+// GetDirectory returns the directory resolver of the run, a lister that is used without one gets a resolver of its
+// own for the scanner.
+func (o *ListerOpts) GetDirectory() *DirectoryResolver {
+	o.directoryOnce.Do(func() {
+		if o.Directory == nil {
+			o.Directory = NewDirectoryResolver(o.Authorizers)
+		}
+	})
+
+	return o.Directory
 }
 
 func GetResourceGroupFromID(id string) *string {
@@ -32,3 +64,8 @@ func GetResourceGroupFromID(id string) *string {
 
 	return nil
 }
+
+// GetManagementGroupScope returns the ARM scope of a management group.
+func GetManagementGroupScope(managementGroupID string) string {
+	return fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s", managementGroupID)
+}
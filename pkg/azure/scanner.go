@@ -0,0 +1,161 @@
+package azure
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// RegisterFunc is the function used to register a scanner, usually this is the RegisterScanner of libnuke.
+type RegisterFunc func(scope registry.Scope, instance *scanner.Scanner) error
+
+// ScannerOptions are the options used to build the scanners for a tenant.
+type ScannerOptions struct {
+	Tenant        *Tenant
+	Regions       []string
+	ResourceTypes map[registry.Scope]types.Collection
+	Logger        *logrus.Logger
+
+	// TagProtection protects the resources within protected resource groups and subscriptions, it is optional
+	TagProtection *TagProtection
+}
+
+// RegisterScanners creates a scanner for the tenant, each subscription and each resource group that was discovered
+// for the tenant and registers them with the provided register function.
+func RegisterScanners(register RegisterFunc, opts *ScannerOptions) error { //nolint:funlen
+	logger := opts.Logger
+	tenant := opts.Tenant
+
+	// Note: the names of principals and role definitions are resolved once for the whole run
+	directory := NewDirectoryResolver(tenant.Authorizers)
+
+	// Note: the resources of a subscription are listed once for the times tracked by Azure Resource Manager, instead
+	// of once per resource group
+	resourceTimes := NewResourceTimesCache(tenant.Authorizers)
+
+	if slices.Contains(opts.Regions, "global") || slices.Contains(opts.Regions, "all") {
+		tenantScanner, scanErr := scanner.New(&scanner.Config{
+			Owner:         "tenant",
+			ResourceTypes: opts.ResourceTypes[TenantScope],
+			Opts: &ListerOpts{
+				Authorizers: tenant.Authorizers,
+				TenantID:    tenant.ID,
+				Directory:   directory,
+			},
+			Logger: logger,
+		})
+		if scanErr != nil {
+			return scanErr
+		}
+
+		if err := register(TenantScope, tenantScanner); err != nil {
+			return err
+		}
+
+		logger.
+			WithField("component", "run").
+			WithField("scope", "tenant").
+			Debug("registering scanner")
+
+		for _, managementGroupID := range tenant.ManagementGroupIds {
+			logger.
+				WithField("component", "run").
+				WithField("scope", "management-group").
+				WithField("management_group_id", managementGroupID).
+				Debug("registering scanner")
+
+			mgScanner, scanErr := scanner.New(&scanner.Config{
+				Owner:         fmt.Sprintf("mg/%s", managementGroupID),
+				ResourceTypes: opts.ResourceTypes[ManagementGroupScope],
+				Opts: &ListerOpts{
+					Authorizers:       tenant.Authorizers,
+					TenantID:          tenant.ID,
+					ManagementGroupID: managementGroupID,
+					Directory:         directory,
+				},
+				Logger: logger,
+			})
+			if scanErr != nil {
+				return scanErr
+			}
+
+			if err := register(ManagementGroupScope, mgScanner); err != nil {
+				return err
+			}
+		}
+
+		for _, subscriptionID := range tenant.SubscriptionIds {
+			logger.
+				WithField("component", "run").
+				WithField("scope", "subscription").
+				WithField("subscription_id", subscriptionID).
+				Debug("registering scanner")
+
+			parts := strings.Split(subscriptionID, "-")
+			subScanner, scanErr := scanner.New(&scanner.Config{
+				Owner:         fmt.Sprintf("sub/%s", parts[:1][0]),
+				ResourceTypes: opts.ResourceTypes[SubscriptionScope],
+				Opts: &ListerOpts{
+					Authorizers:     tenant.Authorizers,
+					TenantID:        tenant.ID,
+					SubscriptionID:  subscriptionID,
+					SubscriptionIDs: tenant.SubscriptionIds,
+					Regions:         opts.Regions,
+					TagProtection:   opts.TagProtection,
+					Directory:       directory,
+					ResourceTimes:   resourceTimes,
+				},
+				Logger: logger,
+			})
+			if scanErr != nil {
+				return scanErr
+			}
+
+			if err := register(SubscriptionScope, subScanner); err != nil {
+				return err
+			}
+		}
+	}
+
+	for subscriptionID, resourceGroups := range tenant.ResourceGroups {
+		for _, rg := range resourceGroups {
+			logger.
+				WithField("component", "run").
+				WithField("scope", "resource-group").
+				WithField("subscription_id", subscriptionID).
+				WithField("resource_group", rg).
+				Debug("registering scanner")
+
+			rgScanner, scanErr := scanner.New(&scanner.Config{
+				Owner:         fmt.Sprintf("sub/%s/rg/%s", subscriptionID, rg),
+				ResourceTypes: opts.ResourceTypes[ResourceGroupScope],
+				Opts: &ListerOpts{
+					Authorizers:    tenant.Authorizers,
+					TenantID:       tenant.ID,
+					SubscriptionID: subscriptionID,
+					ResourceGroup:  rg,
+					Regions:        opts.Regions,
+					TagProtection:  opts.TagProtection,
+					Directory:      directory,
+					ResourceTimes:  resourceTimes,
+				},
+				Logger: logger,
+			})
+			if scanErr != nil {
+				return scanErr
+			}
+
+			if err := register(ResourceGroupScope, rgScanner); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package azure
+
+import (
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+)
+
+// SubscriptionPatterns is a list of subscriptions by ID, display name or a glob on the display name (e.g. sandbox-*)
+type SubscriptionPatterns []string
+
+// Matches returns true if the subscription matches any of the patterns
+func (p SubscriptionPatterns) Matches(id, name string) bool {
+	for _, pattern := range p {
+		if strings.EqualFold(pattern, id) || pattern == name {
+			return true
+		}
+
+		if name == "" || !strings.ContainsAny(pattern, "*?[") {
+			continue
+		}
+
+		// Note: the glob filter is used so the patterns behave the same as the glob filters of the configuration
+		if matched, _ := (&filter.Filter{Type: filter.Glob, Value: pattern}).Match(name); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,13 +2,20 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/subscription/armsubscription"
 )
@@ -16,34 +23,79 @@ import (
 type Tenant struct {
 	Authorizers *Authorizers
 
-	ID              string
-	SubscriptionIds []string
-	TenantIds       []string
+	ID                 string
+	SubscriptionIds    []string
+	TenantIds          []string
+	ManagementGroupIds []string
 
 	Regions        map[string][]string
 	ResourceGroups map[string][]string
+
+	// SubscriptionNames are the display names of the subscriptions by subscription id
+	SubscriptionNames map[string]string
+
+	// ResourceGroupTags are the tags of the resource groups by subscription id and lower case resource group name
+	ResourceGroupTags map[string]map[string]map[string]string
+
+	// SubscriptionTags are the tags of the subscriptions by subscription id, they are only looked up when requested
+	SubscriptionTags map[string]map[string]string
+
+	// SkippedSubscriptions are the subscriptions that were skipped during discovery, keyed by subscription id with
+	// the reason as value
+	SkippedSubscriptions map[string]string
+}
+
+// TenantOptions are the options used to discover a tenant
+type TenantOptions struct {
+	TenantID           string
+	SubscriptionIDs    []string
+	ManagementGroupIDs []string
+	Regions            []string
+
+	// AllManagementGroups scans all management groups visible to the caller when no management groups are requested,
+	// otherwise the management group scope is only scanned for the requested management groups
+	AllManagementGroups bool
+
+	// SubscriptionBlocklist are the subscriptions that must never be scanned, requesting one of them explicitly fails
+	// the discovery
+	SubscriptionBlocklist SubscriptionPatterns
+
+	// SubscriptionAllowlist restricts the subscriptions to those that match, if empty all subscriptions are allowed
+	SubscriptionAllowlist SubscriptionPatterns
+
+	// DiscoveryTimeout is the maximum time the discovery of the tenant may take
+	DiscoveryTimeout time.Duration
+
+	// DiscoveryConcurrency is the number of subscriptions whose resource groups are listed in parallel
+	DiscoveryConcurrency int
+
+	// LookupSubscriptionTags looks up the tags of each subscription, this requires an additional request per
+	// subscription
+	LookupSubscriptionTags bool
 }
 
-func NewTenant( //nolint:gocyclo
-	pctx context.Context, authorizers *Authorizers,
-	tenantID string, subscriptionIDs, regions []string,
-) (*Tenant, error) {
-	ctx, cancel := context.WithTimeout(pctx, time.Second*15)
+func NewTenant(pctx context.Context, authorizers *Authorizers, opts *TenantOptions) (*Tenant, error) { //nolint:funlen
+	ctx, cancel := context.WithTimeout(pctx, opts.DiscoveryTimeout)
 	defer cancel()
 
 	log := logrus.WithField("handler", "NewTenant")
 	log.Trace("start: NewTenant")
 
 	tenant := &Tenant{
-		Authorizers:     authorizers,
-		ID:              tenantID,
-		TenantIds:       make([]string, 0),
-		SubscriptionIds: make([]string, 0),
-		Regions:         make(map[string][]string),
-		ResourceGroups:  make(map[string][]string),
+		Authorizers:          authorizers,
+		ID:                   opts.TenantID,
+		TenantIds:            make([]string, 0),
+		SubscriptionIds:      make([]string, 0),
+		ManagementGroupIds:   make([]string, 0),
+		Regions:              make(map[string][]string),
+		ResourceGroups:       make(map[string][]string),
+		ResourceGroupTags:    make(map[string]map[string]map[string]string),
+		SubscriptionNames:    make(map[string]string),
+		SubscriptionTags:     make(map[string]map[string]string),
+		SkippedSubscriptions: make(map[string]string),
 	}
 
-	tenantClient, err := armsubscription.NewTenantsClient(authorizers.IdentityCreds, nil)
+	tenantClient, err := armsubscription.NewTenantsClient(authorizers.IdentityCreds, authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +105,7 @@ func NewTenant( //nolint:gocyclo
 	for tenantPager.More() {
 		page, err := tenantPager.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			return nil, discoveryError(err, opts.DiscoveryTimeout)
 		}
 		for _, t := range page.Value {
 			log.Tracef("adding tenant: %s", *t.TenantID)
@@ -61,7 +113,21 @@ func NewTenant( //nolint:gocyclo
 		}
 	}
 
-	subClient, err := armsubscription.NewSubscriptionsClient(authorizers.IdentityCreds, nil)
+	managementGroupSubscriptionIDs, err := tenant.discoverManagementGroups(
+		ctx, opts.ManagementGroupIDs, opts.AllManagementGroups)
+	if err != nil {
+		return nil, discoveryError(err, opts.DiscoveryTimeout)
+	}
+
+	// Subscriptions selected by management group are added to the explicitly requested subscriptions, note that
+	// when a management group is requested, subscriptions are always restricted even if the group has none
+	subscriptionIDs := opts.SubscriptionIDs
+	restrictSubscriptions := len(subscriptionIDs) > 0 || len(opts.ManagementGroupIDs) > 0
+	if len(opts.ManagementGroupIDs) > 0 {
+		subscriptionIDs = append(slices.Clone(subscriptionIDs), managementGroupSubscriptionIDs...)
+	}
+
+	subClient, err := armsubscription.NewSubscriptionsClient(authorizers.IdentityCreds, authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -71,52 +137,279 @@ func NewTenant( //nolint:gocyclo
 	for subPager.More() {
 		page, err := subPager.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			return nil, discoveryError(err, opts.DiscoveryTimeout)
 		}
 		for _, s := range page.Value {
 			slog := log.WithField("subscription_id", *s.SubscriptionID)
-			if len(subscriptionIDs) > 0 && !slices.Contains(subscriptionIDs, *s.SubscriptionID) {
+
+			if opts.SubscriptionBlocklist.Matches(*s.SubscriptionID, ptr.ToString(s.DisplayName)) {
+				if slices.Contains(opts.SubscriptionIDs, *s.SubscriptionID) {
+					return nil, fmt.Errorf("subscription %s (%s) was requested, but it is in the subscription-blocklist",
+						*s.SubscriptionID, ptr.ToString(s.DisplayName))
+				}
+
+				slog.Warnf("skipping subscription id: %s (reason: blocklisted)", *s.SubscriptionID)
+				tenant.SkippedSubscriptions[*s.SubscriptionID] = "blocklisted"
+				continue
+			}
+
+			if restrictSubscriptions && !slices.Contains(subscriptionIDs, *s.SubscriptionID) {
 				slog.Warnf("skipping subscription id: %s (reason: not requested)", *s.SubscriptionID)
 				continue
 			}
 
+			if len(opts.SubscriptionAllowlist) > 0 &&
+				!opts.SubscriptionAllowlist.Matches(*s.SubscriptionID, ptr.ToString(s.DisplayName)) {
+				slog.Warnf("skipping subscription id: %s (reason: not in the subscriptions of the tenant)",
+					*s.SubscriptionID)
+				continue
+			}
+
 			slog.Trace("adding subscription")
 			tenant.SubscriptionIds = append(tenant.SubscriptionIds, *s.SubscriptionID)
+			tenant.SubscriptionNames[*s.SubscriptionID] = ptr.ToString(s.DisplayName)
+		}
+	}
+
+	if err := tenant.discoverResourceGroups(ctx, opts.Regions, opts.DiscoveryConcurrency); err != nil {
+		return nil, discoveryError(err, opts.DiscoveryTimeout)
+	}
+
+	if opts.LookupSubscriptionTags {
+		tenant.discoverSubscriptionTags(ctx)
+	}
 
+	if len(tenant.TenantIds) == 0 {
+		return nil, fmt.Errorf("tenant not found: %s", tenant.ID)
+	}
+
+	if tenant.TenantIds[0] != tenant.ID {
+		return nil, fmt.Errorf("tenant ids do not match")
+	}
+
+	return tenant, nil
+}
+
+// SubscriptionName returns the display name of the subscription, or its id if the name is unknown
+func (t *Tenant) SubscriptionName(subscriptionID string) string {
+	if name := t.SubscriptionNames[subscriptionID]; name != "" {
+		return name
+	}
+
+	return subscriptionID
+}
+
+// discoverResourceGroups lists the resource groups of all subscriptions in parallel. Subscriptions the caller has no
+// access to are skipped and recorded in SkippedSubscriptions instead of failing the discovery.
+func (t *Tenant) discoverResourceGroups(ctx context.Context, regions []string, concurrency int) error {
+	log := logrus.WithField("handler", "NewTenant")
+	log.Debugf("configured regions: %v", regions)
+
+	var mu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+
+	for _, subscriptionID := range t.SubscriptionIds {
+		g.Go(func() error {
+			slog := log.WithField("subscription_id", subscriptionID)
 			slog.Trace("listing resource groups")
-			groupsClient, err := armresources.NewResourceGroupsClient(*s.SubscriptionID, authorizers.IdentityCreds, nil)
+
+			groups, groupTags, err := t.listResourceGroups(gctx, subscriptionID, regions)
+
+			var respErr *azcore.ResponseError
+			if errors.As(err, &respErr) && respErr.StatusCode == http.StatusForbidden {
+				reason := fmt.Sprintf("access denied (%s)", respErr.ErrorCode)
+				slog.Warnf("skipping subscription id: %s (reason: %s)", subscriptionID, reason)
+
+				mu.Lock()
+				t.SkippedSubscriptions[subscriptionID] = reason
+				mu.Unlock()
+
+				return nil
+			}
 			if err != nil {
-				return nil, err
+				return fmt.Errorf("unable to list resource groups of subscription %s: %w", subscriptionID, err)
 			}
 
-			slog.Debugf("configured regions: %v", regions)
-			groupsPager := groupsClient.NewListPager(nil)
-			for groupsPager.More() {
-				groupsPage, err := groupsPager.NextPage(ctx)
-				if err != nil {
-					return nil, err
-				}
+			mu.Lock()
+			if len(groups) > 0 {
+				t.ResourceGroups[subscriptionID] = groups
+			}
+			if len(groupTags) > 0 {
+				t.ResourceGroupTags[subscriptionID] = groupTags
+			}
+			mu.Unlock()
 
-				for _, g := range groupsPage.Value {
-					// If the region isn't in the list of regions we want to include, skip it
-					if !slices.Contains(regions, ptr.ToString(g.Location)) && !slices.Contains(regions, "all") {
-						continue
-					}
+			return nil
+		})
+	}
 
-					slog.Debugf("resource group name: %s", *g.Name)
-					tenant.ResourceGroups[*s.SubscriptionID] = append(tenant.ResourceGroups[*s.SubscriptionID], *g.Name)
-				}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	t.SubscriptionIds = slices.DeleteFunc(t.SubscriptionIds, func(id string) bool {
+		_, skipped := t.SkippedSubscriptions[id]
+		return skipped
+	})
+
+	return nil
+}
+
+// listResourceGroups returns the names of the resource groups of the subscription in the requested regions, together
+// with the tags of all resource groups by lower case name. The tags are kept for resource groups outside the requested
+// regions as well, resources in another region than their resource group are still within it.
+func (t *Tenant) listResourceGroups(
+	ctx context.Context, subscriptionID string, regions []string) ([]string, map[string]map[string]string, error) {
+	groupsClient, err := armresources.NewResourceGroupsClient(
+		subscriptionID, t.Authorizers.IdentityCreds, t.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var groups []string
+	groupTags := make(map[string]map[string]string)
+	groupsPager := groupsClient.NewListPager(nil)
+	for groupsPager.More() {
+		groupsPage, err := groupsPager.NextPage(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, g := range groupsPage.Value {
+			if len(g.Tags) > 0 {
+				groupTags[strings.ToLower(ptr.ToString(g.Name))] = toTags(g.Tags)
+			}
+
+			// If the region isn't in the list of regions we want to include, skip it
+			if !slices.Contains(regions, ptr.ToString(g.Location)) && !slices.Contains(regions, "all") {
+				continue
 			}
+
+			logrus.WithField("handler", "NewTenant").
+				WithField("subscription_id", subscriptionID).
+				Debugf("resource group name: %s", *g.Name)
+			groups = append(groups, *g.Name)
 		}
 	}
 
-	if len(tenant.TenantIds) == 0 {
-		return nil, fmt.Errorf("tenant not found: %s", tenant.ID)
+	return groups, groupTags, nil
+}
+
+// discoverSubscriptionTags looks up the tags of the subscriptions. Subscriptions whose tags cannot be read are logged
+// and treated as having no tags.
+func (t *Tenant) discoverSubscriptionTags(ctx context.Context) {
+	log := logrus.WithField("handler", "NewTenant")
+
+	for _, subscriptionID := range t.SubscriptionIds {
+		slog := log.WithField("subscription_id", subscriptionID)
+
+		client, err := armresources.NewTagsClient(
+			subscriptionID, t.Authorizers.IdentityCreds, t.Authorizers.ARMClientOptions())
+		if err != nil {
+			slog.WithError(err).Warn("unable to look up the tags of the subscription")
+			continue
+		}
+
+		res, err := client.GetAtScope(ctx, fmt.Sprintf("/subscriptions/%s", subscriptionID), nil)
+		if err != nil {
+			slog.WithError(err).Warn("unable to look up the tags of the subscription")
+			continue
+		}
+
+		if res.Properties != nil && len(res.Properties.Tags) > 0 {
+			t.SubscriptionTags[subscriptionID] = toTags(res.Properties.Tags)
+		}
 	}
+}
 
-	if tenant.TenantIds[0] != tenant.ID {
-		return nil, fmt.Errorf("tenant ids do not match")
+// toTags converts the tags of the SDK to a plain map
+func toTags(tags map[string]*string) map[string]string {
+	converted := make(map[string]string, len(tags))
+	for key, value := range tags {
+		converted[key] = ptr.ToString(value)
 	}
 
-	return tenant, nil
+	return converted
+}
+
+// discoveryError adds a hint about the discovery timeout when the discovery did not finish in time
+func discoveryError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("tenant discovery did not finish within %s, consider increasing the discovery timeout: %w",
+			timeout, err)
+	}
+
+	return err
+}
+
+// discoverManagementGroups discovers the management group hierarchy of the tenant. If management groups are requested
+// only those and their descendant management groups are used, and the subscriptions that are part of the hierarchy are
+// returned. If no management groups are requested, all management groups visible to the caller are only used when all
+// is set, including the tenant root group, otherwise no management group is scanned.
+func (t *Tenant) discoverManagementGroups(
+	ctx context.Context, managementGroupIDs []string, all bool) ([]string, error) {
+	log := logrus.WithField("handler", "NewTenant")
+
+	if len(managementGroupIDs) == 0 && !all {
+		log.Debug("no management groups requested, skipping management group scope")
+		return nil, nil
+	}
+
+	client, err := armmanagementgroups.NewClient(t.Authorizers.IdentityCreds, t.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(managementGroupIDs) == 0 {
+		log.Trace("listing management groups")
+		pager := client.NewListPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				// Note: management groups are optional, not every principal has access to read them
+				log.WithError(err).Warn("unable to list management groups, skipping management group scope")
+				t.ManagementGroupIds = make([]string, 0)
+				return nil, nil
+			}
+
+			for _, mg := range page.Value {
+				log.Tracef("adding management group: %s", ptr.ToString(mg.Name))
+				t.ManagementGroupIds = append(t.ManagementGroupIds, ptr.ToString(mg.Name))
+			}
+		}
+
+		return nil, nil
+	}
+
+	var subscriptionIDs []string
+	for _, managementGroupID := range managementGroupIDs {
+		if !slices.Contains(t.ManagementGroupIds, managementGroupID) {
+			t.ManagementGroupIds = append(t.ManagementGroupIds, managementGroupID)
+		}
+
+		log.Tracef("listing descendants of management group: %s", managementGroupID)
+		pager := client.NewGetDescendantsPager(managementGroupID, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("unable to list descendants of management group %s: %w", managementGroupID, err)
+			}
+
+			for _, d := range page.Value {
+				name := ptr.ToString(d.Name)
+				switch ptr.ToString(d.Type) {
+				case "Microsoft.Management/managementGroups":
+					if !slices.Contains(t.ManagementGroupIds, name) {
+						t.ManagementGroupIds = append(t.ManagementGroupIds, name)
+					}
+				case "Microsoft.Management/managementGroups/subscriptions", "/subscriptions":
+					subscriptionIDs = append(subscriptionIDs, name)
+				}
+			}
+		}
+	}
+
+	return subscriptionIDs, nil
 }
@@ -0,0 +1,167 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+const deniedSubscriptionID = "00000000-0000-0000-0000-000000000003"
+
+func TestNewTenantSkipsForbiddenSubscriptions(t *testing.T) {
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, "/tenants", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{{"tenantId": azuretest.TenantID}},
+	})
+	server.Respond(http.MethodGet, "/providers/Microsoft.Management/managementGroups", http.StatusForbidden,
+		`{"error":{"code":"AuthorizationFailed","message":"denied"}}`)
+	server.Respond(http.MethodGet, "/subscriptions", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{
+			{"subscriptionId": azuretest.SubscriptionID},
+			{"subscriptionId": deniedSubscriptionID},
+		},
+	})
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups", http.StatusOK,
+		map[string]interface{}{
+			"value": []map[string]string{
+				{"name": "rg-east", "location": "eastus"},
+				{"name": "rg-west", "location": "westus"},
+			},
+		})
+	server.Respond(http.MethodGet, "/subscriptions/"+deniedSubscriptionID+"/resourcegroups", http.StatusForbidden,
+		`{"error":{"code":"AuthorizationFailed","message":"denied"}}`)
+
+	tenant, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:             azuretest.TenantID,
+		Regions:              []string{"global", "eastus"},
+		AllManagementGroups:  true,
+		DiscoveryTimeout:     10 * time.Second,
+		DiscoveryConcurrency: 2,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{azuretest.SubscriptionID}, tenant.SubscriptionIds)
+	assert.Equal(t, []string{"rg-east"}, tenant.ResourceGroups[azuretest.SubscriptionID])
+	assert.Equal(t, "access denied (AuthorizationFailed)", tenant.SkippedSubscriptions[deniedSubscriptionID])
+	assert.Empty(t, tenant.ManagementGroupIds)
+}
+
+func TestNewTenantManagementGroupsOptIn(t *testing.T) {
+	server := subscriptionsServer(t)
+	server.Respond(http.MethodGet, "/providers/Microsoft.Management/managementGroups", http.StatusOK,
+		map[string]interface{}{"value": []map[string]string{{"name": azuretest.TenantID}, {"name": "sandbox"}}})
+	server.Respond(http.MethodGet, "/subscriptions/*/resourcegroups", http.StatusOK,
+		map[string]interface{}{"value": []interface{}{}})
+
+	tenant, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:         azuretest.TenantID,
+		SubscriptionIDs:  []string{azuretest.SubscriptionID},
+		Regions:          []string{"all"},
+		DiscoveryTimeout: 10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, tenant.ManagementGroupIds)
+	assert.Empty(t, server.Requests(http.MethodGet, "/providers/Microsoft.Management/managementGroups"))
+
+	tenant, err = azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:            azuretest.TenantID,
+		SubscriptionIDs:     []string{azuretest.SubscriptionID},
+		Regions:             []string{"all"},
+		AllManagementGroups: true,
+		DiscoveryTimeout:    10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{azuretest.TenantID, "sandbox"}, tenant.ManagementGroupIds)
+}
+
+func subscriptionsServer(t *testing.T) *azuretest.Server {
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, "/tenants", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{{"tenantId": azuretest.TenantID}},
+	})
+	server.Respond(http.MethodGet, "/providers/Microsoft.Management/managementGroups", http.StatusOK,
+		map[string]interface{}{"value": []interface{}{}})
+	server.Respond(http.MethodGet, "/subscriptions", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{
+			{"subscriptionId": azuretest.SubscriptionID, "displayName": "sandbox-dev"},
+			{"subscriptionId": "00000000-0000-0000-0000-000000000004", "displayName": "sandbox-prod"},
+			{"subscriptionId": "00000000-0000-0000-0000-000000000005", "displayName": "production"},
+		},
+	})
+
+	return server
+}
+
+func TestNewTenantSubscriptionBlocklist(t *testing.T) {
+	server := subscriptionsServer(t)
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups", http.StatusOK,
+		map[string]interface{}{"value": []interface{}{}})
+
+	tenant, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:              azuretest.TenantID,
+		Regions:               []string{"all"},
+		SubscriptionBlocklist: azure.SubscriptionPatterns{"production", "00000000-0000-0000-0000-000000000004"},
+		DiscoveryTimeout:      10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{azuretest.SubscriptionID}, tenant.SubscriptionIds)
+	assert.Equal(t, map[string]string{
+		"00000000-0000-0000-0000-000000000004": "blocklisted",
+		"00000000-0000-0000-0000-000000000005": "blocklisted",
+	}, tenant.SkippedSubscriptions)
+}
+
+func TestNewTenantRequestedSubscriptionBlocklisted(t *testing.T) {
+	server := subscriptionsServer(t)
+
+	_, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:              azuretest.TenantID,
+		SubscriptionIDs:       []string{"00000000-0000-0000-0000-000000000005"},
+		Regions:               []string{"all"},
+		SubscriptionBlocklist: azure.SubscriptionPatterns{"prod*"},
+		DiscoveryTimeout:      10 * time.Second,
+	})
+	assert.EqualError(t, err, "subscription 00000000-0000-0000-0000-000000000005 (production) was requested, "+
+		"but it is in the subscription-blocklist")
+}
+
+func TestNewTenantSubscriptionAllowlist(t *testing.T) {
+	server := subscriptionsServer(t)
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups", http.StatusOK,
+		map[string]interface{}{"value": []interface{}{}})
+
+	tenant, err := azure.NewTenant(context.TODO(), server.Authorizers(), &azure.TenantOptions{
+		TenantID:              azuretest.TenantID,
+		Regions:               []string{"all"},
+		SubscriptionAllowlist: azure.SubscriptionPatterns{"sandbox-*"},
+		SubscriptionBlocklist: azure.SubscriptionPatterns{"sandbox-prod"},
+		DiscoveryTimeout:      10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{azuretest.SubscriptionID}, tenant.SubscriptionIds)
+	assert.Equal(t, map[string]string{azuretest.SubscriptionID: "sandbox-dev"}, tenant.SubscriptionNames)
+	assert.Equal(t, map[string]string{"00000000-0000-0000-0000-000000000004": "blocklisted"},
+		tenant.SkippedSubscriptions)
+}
+
+func TestSubscriptionPatterns(t *testing.T) {
+	patterns := azure.SubscriptionPatterns{"sandbox-*", "Production", "00000000-0000-0000-0000-0000000000AB"}
+
+	assert.True(t, patterns.Matches("00000000-0000-0000-0000-000000000001", "sandbox-dev"))
+	assert.True(t, patterns.Matches("00000000-0000-0000-0000-000000000002", "Production"))
+	assert.True(t, patterns.Matches("00000000-0000-0000-0000-0000000000ab", ""))
+	assert.False(t, patterns.Matches("00000000-0000-0000-0000-000000000003", "production"))
+	assert.False(t, patterns.Matches("00000000-0000-0000-0000-000000000004", "dev-sandbox"))
+	assert.False(t, azure.SubscriptionPatterns{}.Matches("00000000-0000-0000-0000-000000000004", "dev"))
+}
@@ -0,0 +1,264 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+var _ policy.Policy = &Throttle{}
+
+// ThrottleOptions are the options of the throttle shared by every ARM and Microsoft Graph client
+type ThrottleOptions struct {
+	// RequestsPerSecond is the maximum number of requests per second per API host, 0 means unlimited
+	RequestsPerSecond float64
+
+	// MaxRetries is the maximum number of times a single throttled request is retried
+	MaxRetries int
+
+	// RetryBudget is the total number of retries of throttled requests for the whole run, 0 means unlimited
+	RetryBudget int
+
+	// MaxRetryDelay caps the time that is waited before a throttled request is retried
+	MaxRetryDelay time.Duration
+
+	// Observer is notified of every response and transport error, it is optional
+	Observer RequestObserver
+}
+
+// RequestObserver is notified of every response that is received by the throttle, including the throttled responses
+// of requests that are retried. A request that failed without a response, such as a network error, is observed with
+// the status code 0.
+type RequestObserver interface {
+	ObserveRequest(host string, statusCode int, throttled bool)
+}
+
+// Throttle limits the rate of requests per API host and retries throttled requests, honoring the Retry-After
+// headers sent by Azure. It is used as an azcore pipeline policy for ARM clients and as a http.RoundTripper for
+// the Microsoft Graph clients.
+type Throttle struct {
+	opts *ThrottleOptions
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+
+	retriesLeft atomic.Int64
+}
+
+// NewThrottle creates a new throttle with the provided options
+func NewThrottle(opts *ThrottleOptions) *Throttle {
+	t := &Throttle{
+		opts:     opts,
+		limiters: make(map[string]*rate.Limiter),
+	}
+	t.retriesLeft.Store(int64(opts.RetryBudget))
+
+	return t
+}
+
+// Do implements the policy.Policy interface of azcore
+func (t *Throttle) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	return t.send(raw.Context(), raw.URL.Host, req.Next, req.RewindBody)
+}
+
+// Transport wraps the base transport, so that the requests sent with it are throttled
+func (t *Throttle) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &throttledTransport{
+		throttle: t,
+		base:     base,
+	}
+}
+
+func (t *Throttle) send(
+	ctx context.Context, host string, send func() (*http.Response, error), rewind func() error) (*http.Response, error) {
+	log := logrus.WithField("component", "throttle").WithField("host", host)
+
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter(host).Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := send()
+		if err != nil {
+			if t.opts.Observer != nil {
+				t.opts.Observer.ObserveRequest(host, 0, false)
+			}
+
+			return resp, err
+		}
+
+		throttled := isThrottled(resp)
+		if t.opts.Observer != nil {
+			t.opts.Observer.ObserveRequest(host, resp.StatusCode, throttled)
+		}
+
+		if !throttled {
+			return resp, nil
+		}
+
+		if attempt >= t.opts.MaxRetries || !t.takeRetry() {
+			log.Warnf("request throttled (%d), not retrying", resp.StatusCode)
+			return resp, nil
+		}
+
+		delay := t.retryDelay(resp, attempt)
+		log.Debugf("request throttled (%d), retrying in %s", resp.StatusCode, delay)
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		if err := rewind(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// limiter returns the token bucket of the host
+func (t *Throttle) limiter(host string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	l, ok := t.limiters[host]
+	if !ok {
+		l = rate.NewLimiter(rate.Inf, 0)
+		if t.opts.RequestsPerSecond > 0 {
+			l = rate.NewLimiter(rate.Limit(t.opts.RequestsPerSecond), int(math.Max(1, math.Ceil(t.opts.RequestsPerSecond))))
+		}
+		t.limiters[host] = l
+	}
+
+	return l
+}
+
+// takeRetry returns true if the retry budget allows another retry
+func (t *Throttle) takeRetry() bool {
+	if t.opts.RetryBudget <= 0 {
+		return true
+	}
+
+	return t.retriesLeft.Add(-1) >= 0
+}
+
+// retryDelay returns how long to wait before retrying, the Retry-After headers are used when present, otherwise
+// an exponential backoff starting at one second is used.
+func (t *Throttle) retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := time.Duration(1<<attempt) * time.Second
+
+	if v, ok := retryAfter(resp.Header); ok {
+		delay = v
+	}
+
+	if t.opts.MaxRetryDelay > 0 && delay > t.opts.MaxRetryDelay {
+		delay = t.opts.MaxRetryDelay
+	}
+
+	return delay
+}
+
+// retryAfter parses the retry headers that are sent by ARM and Microsoft Graph
+func retryAfter(header http.Header) (time.Duration, bool) {
+	for _, name := range []string{"Retry-After-Ms", "X-Ms-Retry-After-Ms"} {
+		if v := header.Get(name); v != "" {
+			if ms, err := strconv.Atoi(v); err == nil {
+				return time.Duration(ms) * time.Millisecond, true
+			}
+		}
+	}
+
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// isThrottled returns true for too many requests, or service unavailable with a Retry-After header
+func isThrottled(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if resp.StatusCode == http.StatusServiceUnavailable {
+		_, ok := retryAfter(resp.Header)
+		return ok
+	}
+
+	return false
+}
+
+type throttledTransport struct {
+	throttle *Throttle
+	base     http.RoundTripper
+}
+
+func (tt *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Note: the body is buffered when it can not be recreated, otherwise a retry would send an empty body
+	getBody := req.GetBody
+	attemptReq := req
+	if req.Body != nil && req.Body != http.NoBody && getBody == nil {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		getBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+
+		attemptReq = req.Clone(req.Context())
+		attemptReq.Body, _ = getBody()
+	}
+
+	return tt.throttle.send(req.Context(), req.URL.Host,
+		func() (*http.Response, error) {
+			return tt.base.RoundTrip(attemptReq)
+		},
+		func() error {
+			attemptReq = req.Clone(req.Context())
+			if getBody == nil {
+				return nil
+			}
+
+			body, err := getBody()
+			if err != nil {
+				return err
+			}
+			attemptReq.Body = body
+
+			return nil
+		})
+}
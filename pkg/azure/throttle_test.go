@@ -0,0 +1,127 @@
+package azure
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newThrottledServer(t *testing.T, throttled int32) (*httptest.Server, *atomic.Int32) {
+	calls := &atomic.Int32{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, "payload", string(body))
+
+		if calls.Add(1) <= throttled {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, calls
+}
+
+func TestThrottleRetriesThrottledRequests(t *testing.T) {
+	server, calls := newThrottledServer(t, 2)
+
+	throttle := NewThrottle(&ThrottleOptions{MaxRetries: 3})
+	client := &http.Client{Transport: throttle.Transport(nil)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestThrottleRetryBudget(t *testing.T) {
+	server, calls := newThrottledServer(t, 10)
+
+	throttle := NewThrottle(&ThrottleOptions{MaxRetries: 5, RetryBudget: 1})
+	client := &http.Client{Transport: throttle.Transport(nil)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestThrottleRetryDelay(t *testing.T) {
+	throttle := NewThrottle(&ThrottleOptions{MaxRetryDelay: 10 * time.Second})
+
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(t, 4*time.Second, throttle.retryDelay(resp, 2))
+	assert.Equal(t, 10*time.Second, throttle.retryDelay(resp, 6))
+
+	resp.Header.Set("Retry-After", "3")
+	assert.Equal(t, 3*time.Second, throttle.retryDelay(resp, 0))
+
+	resp.Header.Set("x-ms-retry-after-ms", "250")
+	assert.Equal(t, 250*time.Millisecond, throttle.retryDelay(resp, 0))
+}
+
+func TestIsThrottled(t *testing.T) {
+	assert.True(t, isThrottled(&http.Response{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, isThrottled(&http.Response{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, isThrottled(&http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Retry-After": []string{"1"}},
+	}))
+	assert.False(t, isThrottled(&http.Response{StatusCode: http.StatusOK}))
+}
+
+type testObserver struct {
+	requests  int
+	throttled int
+	codes     []int
+}
+
+func (o *testObserver) ObserveRequest(_ string, statusCode int, throttled bool) {
+	o.requests++
+	o.codes = append(o.codes, statusCode)
+	if throttled {
+		o.throttled++
+	}
+}
+
+func TestThrottleObserver(t *testing.T) {
+	server, _ := newThrottledServer(t, 2)
+
+	observer := &testObserver{}
+	throttle := NewThrottle(&ThrottleOptions{MaxRetries: 3, Observer: observer})
+	client := &http.Client{Transport: throttle.Transport(nil)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, observer.requests)
+	assert.Equal(t, 2, observer.throttled)
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection reset by peer")
+}
+
+func TestThrottleObserverTransportError(t *testing.T) {
+	observer := &testObserver{}
+	throttle := NewThrottle(&ThrottleOptions{MaxRetries: 3, Observer: observer})
+	client := &http.Client{Transport: throttle.Transport(failingTransport{})}
+
+	_, err := client.Get("https://management.azure.com/subscriptions")
+	assert.ErrorContains(t, err, "connection reset by peer")
+	assert.Equal(t, []int{0}, observer.codes)
+	assert.Equal(t, 0, observer.throttled)
+}
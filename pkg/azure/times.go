@@ -0,0 +1,104 @@
+package azure
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// ResourceTimes are the creation and last change time of a resource as tracked by Azure Resource Manager
+type ResourceTimes struct {
+	CreatedTime *time.Time
+	ChangedTime *time.Time
+}
+
+// ResourceTimesCache caches the times tracked by Azure Resource Manager for the resources of each subscription. The
+// resources of a subscription are listed once, the first time a resource of the subscription is looked up, and shared
+// by the listers of all scanners of the run.
+type ResourceTimesCache struct {
+	authorizers *Authorizers
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscriptionResourceTimes
+}
+
+type subscriptionResourceTimes struct {
+	once  sync.Once
+	times map[string]*ResourceTimes
+}
+
+// NewResourceTimesCache creates a new resource times cache with an empty cache
+func NewResourceTimesCache(authorizers *Authorizers) *ResourceTimesCache {
+	return &ResourceTimesCache{
+		authorizers:   authorizers,
+		subscriptions: make(map[string]*subscriptionResourceTimes),
+	}
+}
+
+// Get returns the times of the resource with the given ID in the subscription, nil is returned if the times are not
+// known.
+func (c *ResourceTimesCache) Get(ctx context.Context, subscriptionID, id string) *ResourceTimes {
+	c.mu.Lock()
+	sub, ok := c.subscriptions[subscriptionID]
+	if !ok {
+		sub = &subscriptionResourceTimes{times: make(map[string]*ResourceTimes)}
+		c.subscriptions[subscriptionID] = sub
+	}
+	c.mu.Unlock()
+
+	sub.once.Do(func() {
+		if err := c.load(ctx, subscriptionID, sub.times); err != nil {
+			logrus.
+				WithField("s", subscriptionID).
+				WithError(err).
+				Warn("unable to list resource creation times")
+		}
+	})
+
+	return sub.times[strings.ToLower(id)]
+}
+
+func (c *ResourceTimesCache) load(ctx context.Context, subscriptionID string, times map[string]*ResourceTimes) error {
+	client, err := armresources.NewClient(subscriptionID, c.authorizers.IdentityCreds, c.authorizers.ARMClientOptions())
+	if err != nil {
+		return err
+	}
+
+	pager := client.NewListPager(&armresources.ClientListOptions{
+		Expand: ptr.String("createdTime,changedTime"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range page.Value {
+			times[strings.ToLower(ptr.ToString(r.ID))] = &ResourceTimes{
+				CreatedTime: r.CreatedTime,
+				ChangedTime: r.ChangedTime,
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetResourceTimes returns the times tracked by Azure Resource Manager for the resource with the given ID. This is
+// used for resource types whose SDK model does not expose the systemData of the resource. Nil is returned if the
+// times are not known.
+func (o *ListerOpts) GetResourceTimes(ctx context.Context, id string) *ResourceTimes {
+	o.resourceTimesOnce.Do(func() {
+		if o.ResourceTimes == nil {
+			o.ResourceTimes = NewResourceTimesCache(o.Authorizers)
+		}
+	})
+
+	return o.ResourceTimes.Get(ctx, o.SubscriptionID, id)
+}
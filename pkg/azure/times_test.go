@@ -0,0 +1,41 @@
+package azure_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestResourceTimesCacheListsSubscriptionOnce(t *testing.T) {
+	rgPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/"
+
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resources", http.StatusOK,
+		map[string]interface{}{
+			"value": []map[string]string{
+				{"id": rgPath + "rg-a/providers/Microsoft.Compute/disks/disk-a", "createdTime": "2024-03-10T12:00:00Z"},
+				{"id": rgPath + "rg-b/providers/Microsoft.Compute/disks/disk-b", "changedTime": "2024-03-11T09:15:00Z"},
+			},
+		})
+
+	cache := azure.NewResourceTimesCache(server.Authorizers())
+
+	a := (&azure.ListerOpts{SubscriptionID: azuretest.SubscriptionID, ResourceGroup: "rg-a", ResourceTimes: cache}).
+		GetResourceTimes(context.TODO(), rgPath+"RG-A/providers/Microsoft.Compute/disks/disk-a")
+	require.NotNil(t, a)
+	assert.Equal(t, "2024-03-10T12:00:00Z", a.CreatedTime.Format("2006-01-02T15:04:05Z07:00"))
+
+	b := (&azure.ListerOpts{SubscriptionID: azuretest.SubscriptionID, ResourceGroup: "rg-b", ResourceTimes: cache}).
+		GetResourceTimes(context.TODO(), rgPath+"rg-b/providers/Microsoft.Compute/disks/disk-b")
+	require.NotNil(t, b)
+	assert.Nil(t, b.CreatedTime)
+
+	assert.Nil(t, cache.Get(context.TODO(), azuretest.SubscriptionID, rgPath+"rg-c/providers/Microsoft.Compute/disks/c"))
+	assert.Len(t, server.Requests(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resources"), 1)
+}
@@ -0,0 +1,59 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/auth"
+	"github.com/hashicorp/go-azure-sdk/sdk/environments"
+)
+
+var _ auth.Authorizer = &TokenCredentialAuthorizer{}
+
+// TokenCredentialAuthorizer is a hashicorp auth.Authorizer that obtains its tokens from an azcore.TokenCredential,
+// this allows the Graph and ARM clients to use credentials that only exist in azidentity.
+type TokenCredentialAuthorizer struct {
+	credential azcore.TokenCredential
+	scopes     []string
+}
+
+// NewTokenCredentialAuthorizer returns an authorizer for the given api that is backed by the credential
+func NewTokenCredentialAuthorizer(
+	credential azcore.TokenCredential, api environments.Api) (*TokenCredentialAuthorizer, error) {
+	resource, ok := api.ResourceIdentifier()
+	if !ok || resource == nil {
+		return nil, fmt.Errorf("unable to determine resource identifier for api %q", api.Name())
+	}
+
+	return &TokenCredentialAuthorizer{
+		credential: credential,
+		scopes:     []string{fmt.Sprintf("%s/.default", *resource)},
+	}, nil
+}
+
+// Token obtains a new access token for the configured tenant
+func (a *TokenCredentialAuthorizer) Token(ctx context.Context, _ *http.Request) (*oauth2.Token, error) {
+	token, err := a.credential.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: a.scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.Token,
+		TokenType:   "Bearer",
+		Expiry:      token.ExpiresOn,
+	}, nil
+}
+
+// AuxiliaryTokens is not supported, auxiliary tenants are not used
+func (a *TokenCredentialAuthorizer) AuxiliaryTokens(_ context.Context, _ *http.Request) ([]*oauth2.Token, error) {
+	return nil, nil
+}
@@ -0,0 +1,49 @@
+package azure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/environments"
+)
+
+type testCredential struct {
+	scopes []string
+}
+
+func (c *testCredential) GetToken(_ context.Context, opts policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.scopes = opts.Scopes
+	return azcore.AccessToken{Token: "token", ExpiresOn: time.Unix(1700000000, 0)}, nil
+}
+
+func TestTokenCredentialAuthorizer(t *testing.T) {
+	creds := &testCredential{}
+
+	authorizer, err := NewTokenCredentialAuthorizer(creds, environments.AzurePublic().MicrosoftGraph)
+	assert.NoError(t, err)
+
+	token, err := authorizer.Token(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, []string{"https://graph.microsoft.com/.default"}, creds.scopes)
+
+	aux, err := authorizer.AuxiliaryTokens(context.TODO(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, aux)
+}
+
+func TestConfigureAuthExclusiveModes(t *testing.T) {
+	_, err := ConfigureAuth(context.TODO(), &AuthOptions{
+		Environment:          "global",
+		UseManagedIdentity:   true,
+		UseDefaultCredential: true,
+	})
+	assert.Error(t, err)
+}
@@ -1,9 +1,14 @@
 package azure
 
 import (
+	"net/http"
+	"slices"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/hashicorp/go-azure-sdk/sdk/auth"
 	"github.com/hashicorp/go-azure-sdk/sdk/auth/autorest"
+	"github.com/manicminer/hamilton/msgraph"
 )
 
 type Authorizers struct {
@@ -14,4 +19,73 @@ type Authorizers struct {
 	ResourceManager auth.Authorizer
 
 	IdentityCreds azcore.TokenCredential
+
+	// ClientOptions are the options every ARM client is created with, when nil the defaults of the SDK are used
+	ClientOptions *arm.ClientOptions
+
+	// GraphEndpoint overrides the endpoint of the Microsoft Graph clients, when empty the default is used
+	GraphEndpoint string
+
+	// GraphHTTPClient overrides the http client the Microsoft Graph clients send their requests with
+	GraphHTTPClient *http.Client
+
+	// Throttle limits the request rate of every ARM and Microsoft Graph client and retries throttled requests
+	Throttle *Throttle
+
+	// LockReleaser handles the removals of ARM resources that are blocked by management locks
+	LockReleaser *LockReleaser
+}
+
+// ARMClientOptions returns a copy of the options for ARM clients, the copy can be modified by the caller, for
+// example to set the API version, without affecting other clients.
+func (a *Authorizers) ARMClientOptions() *arm.ClientOptions {
+	return a.armClientOptions(true)
+}
+
+// armClientOptions returns a copy of the options for ARM clients, the lock releaser is only part of the pipeline if
+// requested, the lock releaser itself uses clients without it.
+func (a *Authorizers) armClientOptions(withLockReleaser bool) *arm.ClientOptions {
+	clientOptions := arm.ClientOptions{}
+	if a.ClientOptions != nil {
+		clientOptions = *a.ClientOptions
+	}
+
+	if a.Throttle != nil {
+		clientOptions.PerRetryPolicies = append(slices.Clone(clientOptions.PerRetryPolicies), a.Throttle)
+
+		// Note: throttled requests are retried by the throttle, so that the retry budget is honored, everything
+		// else is still retried by the retry policy of azcore.
+		clientOptions.Retry.StatusCodes = []int{
+			http.StatusRequestTimeout,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+
+	if withLockReleaser && a.LockReleaser != nil {
+		clientOptions.PerCallPolicies = append(slices.Clone(clientOptions.PerCallPolicies), a.LockReleaser)
+	}
+
+	return &clientOptions
+}
+
+// ConfigureGraphClient applies the endpoint and http client overrides to a Microsoft Graph client
+func (a *Authorizers) ConfigureGraphClient(client *msgraph.Client) {
+	if a.GraphEndpoint != "" {
+		client.Endpoint = a.GraphEndpoint
+	}
+
+	if a.GraphHTTPClient != nil {
+		client.RetryableClient.HTTPClient = a.GraphHTTPClient
+	}
+
+	if a.Throttle != nil {
+		httpClient := *client.RetryableClient.HTTPClient
+		httpClient.Transport = a.Throttle.Transport(httpClient.Transport)
+
+		client.RetryableClient.HTTPClient = &httpClient
+		client.RetryableClient.RetryMax = 0
+	}
 }
@@ -0,0 +1,22 @@
+package azuretest
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+var _ azcore.TokenCredential = &Credential{}
+
+// Credential is a fake credential that always returns the same token
+type Credential struct{}
+
+// GetToken returns a static token that is valid for an hour
+func (c *Credential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{
+		Token:     "fake-token",
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
@@ -0,0 +1,235 @@
+// Package azuretest provides a fake Azure Resource Manager and Microsoft Graph endpoint, so that the listers and
+// removers of resources can be tested without a tenant.
+package azuretest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/hashicorp/go-azure-sdk/sdk/auth/autorest"
+	"github.com/hashicorp/go-azure-sdk/sdk/environments"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const (
+	// TenantID is the tenant ID that can be used in tests
+	TenantID = "00000000-0000-0000-0000-000000000001"
+	// SubscriptionID is the subscription ID that can be used in tests
+	SubscriptionID = "00000000-0000-0000-0000-000000000002"
+)
+
+// Request is a request that was received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+// Server is a fake ARM and Microsoft Graph endpoint, every request has to match a registered route, any other
+// request fails the test.
+type Server struct {
+	*httptest.Server
+
+	t        *testing.T
+	mu       sync.Mutex
+	routes   []*route
+	requests []Request
+}
+
+// NewServer starts a new TLS server that is closed when the test finishes. TLS is required because the SDK refuses
+// to send bearer tokens over plain http.
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := &Server{t: t}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Handle registers a handler for the method and path. The path is matched case-insensitively without the query
+// string, a `*` matches exactly one path segment.
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = append(s.routes, &route{
+		method:  method,
+		path:    path,
+		handler: handler,
+	})
+}
+
+// Respond registers a static response for the method and path. A string or []byte body is sent as is, anything
+// else is encoded as JSON.
+func (s *Server) Respond(method, path string, status int, body interface{}) {
+	s.t.Helper()
+
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		data = []byte(b)
+	case []byte:
+		data = b
+	default:
+		var err error
+		data, err = json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("unable to encode response for %s %s: %s", method, path, err)
+		}
+	}
+
+	s.Handle(method, path, func(w http.ResponseWriter, _ *http.Request) {
+		if data != nil {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(data)
+	})
+}
+
+// RespondWithFixture registers a response with the contents of a file in the testdata directory of the package
+// that is being tested.
+func (s *Server) RespondWithFixture(method, path string, status int, fixture string) {
+	s.t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		s.t.Fatalf("unable to read fixture: %s", err)
+	}
+
+	s.Respond(method, path, status, data)
+}
+
+// Requests returns the requests that were received for the method and path.
+func (s *Server) Requests(method, path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var requests []Request
+	for _, r := range s.requests {
+		if r.Method == method && matchPath(path, r.Path) {
+			requests = append(requests, r)
+		}
+	}
+
+	return requests
+}
+
+// Authorizers returns authorizers with a fake credential that send all ARM and Graph requests to the server.
+func (s *Server) Authorizers() *azure.Authorizers {
+	s.t.Helper()
+
+	creds := &Credential{}
+
+	graphAuthorizer, err := azure.NewTokenCredentialAuthorizer(creds, environments.AzurePublic().MicrosoftGraph)
+	if err != nil {
+		s.t.Fatalf("unable to create graph authorizer: %s", err)
+	}
+
+	mgmtAuthorizer, err := azure.NewTokenCredentialAuthorizer(creds, environments.AzurePublic().ResourceManager)
+	if err != nil {
+		s.t.Fatalf("unable to create management authorizer: %s", err)
+	}
+
+	return &azure.Authorizers{
+		Graph:           autorest.AutorestAuthorizer(graphAuthorizer),
+		Management:      autorest.AutorestAuthorizer(mgmtAuthorizer),
+		MicrosoftGraph:  graphAuthorizer,
+		ResourceManager: mgmtAuthorizer,
+		IdentityCreds:   creds,
+		ClientOptions: &arm.ClientOptions{
+			ClientOptions: azcore.ClientOptions{
+				Cloud: cloud.Configuration{
+					ActiveDirectoryAuthorityHost: s.URL,
+					Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+						cloud.ResourceManager: {
+							Audience: "https://management.core.windows.net/",
+							Endpoint: s.URL,
+						},
+					},
+				},
+				Retry: policy.RetryOptions{
+					MaxRetries: -1,
+				},
+				Transport: s.Client(),
+			},
+			DisableRPRegistration: true,
+		},
+		GraphEndpoint:   s.URL,
+		GraphHTTPClient: s.Client(),
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Body:   body,
+	})
+
+	var handler http.HandlerFunc
+	for _, rt := range s.routes {
+		if rt.method == r.Method && matchPath(rt.path, r.URL.Path) {
+			handler = rt.handler
+		}
+	}
+	s.mu.Unlock()
+
+	if handler == nil {
+		s.t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprintf(w, `{"error":{"code":"NotFound","message":"no route for %s %s"}}`, r.Method, r.URL.Path)
+		return
+	}
+
+	handler(w, r)
+}
+
+// matchPath returns true if the path matches the pattern, segments are compared case-insensitively and a `*`
+// matches any single segment.
+func matchPath(pattern, path string) bool {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i := range patternParts {
+		if patternParts[i] != "*" && !strings.EqualFold(patternParts[i], pathParts[i]) {
+			return false
+		}
+	}
+
+	return true
+}
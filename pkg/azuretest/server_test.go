@@ -0,0 +1,14 @@
+package azuretest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPath(t *testing.T) {
+	assert.True(t, matchPath("/subscriptions/sub/resourcegroups", "/subscriptions/sub/resourceGroups"))
+	assert.True(t, matchPath("/subscriptions/*/resourcegroups", "/subscriptions/sub/resourcegroups/"))
+	assert.False(t, matchPath("/subscriptions/*/resourcegroups", "/subscriptions/sub/resourcegroups/rg"))
+	assert.False(t, matchPath("/subscriptions/sub", "/subscriptions/other"))
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/azure-nuke/pkg/commands/global"
+	"github.com/ekristen/azure-nuke/pkg/common"
+	"github.com/ekristen/azure-nuke/pkg/config"
+	_ "github.com/ekristen/azure-nuke/resources"
+)
+
+func validate(_ context.Context, cmd *cli.Command) error {
+	issues, err := config.Validate(cmd.String("config"))
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if count := issues.Errors(); count > 0 {
+		return fmt.Errorf("%s has %d error(s)", cmd.String("config"), count)
+	}
+
+	fmt.Printf("%s is valid\n", cmd.String("config"))
+
+	return nil
+}
+
+func explain(_ context.Context, cmd *cli.Command) error {
+	parsedConfig, err := config.New(libconfig.Options{
+		Path:         cmd.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		Log:          logrus.WithField("component", "config"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return parsedConfig.Explain(os.Stdout, cmd.String("tenant-id"), cmd.StringSlice("include"), cmd.StringSlice("exclude"))
+}
+
+func schema(_ context.Context, cmd *cli.Command) error {
+	w := os.Stdout
+	if path := cmd.String("output-file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(config.Schema())
+}
+
+func init() {
+	configFlag := &cli.StringFlag{
+		Name:  "config",
+		Usage: "path to config file",
+		Value: "config.yaml",
+	}
+
+	cmd := &cli.Command{
+		Name:  "config",
+		Usage: "validate a configuration or explain how it applies to a tenant",
+		Commands: []*cli.Command{
+			{
+				Name:   "validate",
+				Usage:  "check the configuration for unknown keys, resource types, properties and deprecated keys",
+				Flags:  append([]cli.Flag{configFlag}, global.Flags()...),
+				Before: global.Before,
+				Action: validate,
+			},
+			{
+				Name:  "explain",
+				Usage: "show the resource types per scope and the merged filters that apply to a tenant",
+				Flags: append([]cli.Flag{
+					configFlag,
+					&cli.StringFlag{
+						Name:     "tenant-id",
+						Usage:    "the tenant-id to explain the configuration for",
+						Sources:  cli.EnvVars("AZURE_TENANT_ID"),
+						Required: true,
+					},
+					&cli.StringSliceFlag{
+						Name:  "include",
+						Usage: "only include this specific resource",
+					},
+					&cli.StringSliceFlag{
+						Name:  "exclude",
+						Usage: "exclude this specific resource (this overrides everything)",
+					},
+				}, global.Flags()...),
+				Before: global.Before,
+				Action: explain,
+			},
+			{
+				Name:  "schema",
+				Usage: "write the json schema of the configuration, for editors to validate and autocomplete it",
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "output-file",
+						Usage: "write the schema to this file instead of stdout",
+					},
+				}, global.Flags()...),
+				Before: global.Before,
+				Action: schema,
+			},
+		},
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/azure-nuke/pkg/commands/global"
+	"github.com/ekristen/azure-nuke/pkg/common"
+	"github.com/ekristen/azure-nuke/pkg/diff"
+)
+
+// ExitCodeDifferences is the exit code when differences were found, errors exit with 1
+const ExitCodeDifferences = 2
+
+func execute(_ context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 2 {
+		return fmt.Errorf("diff requires exactly two files: <old> <new>")
+	}
+
+	format, err := diff.ParseFormat(cmd.String("format"))
+	if err != nil {
+		return err
+	}
+
+	failOn := make([]diff.Kind, 0)
+	for _, k := range cmd.StringSlice("fail-on") {
+		if k == "none" {
+			continue
+		}
+
+		kind, err := diff.ParseKind(k)
+		if err != nil {
+			return err
+		}
+
+		failOn = append(failOn, kind)
+	}
+
+	oldSnapshot, err := diff.Load(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	newSnapshot, err := diff.Load(cmd.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	result := diff.Compare(oldSnapshot, newSnapshot, cmd.StringSlice("ignore-property"))
+	if err := result.Write(os.Stdout, format); err != nil {
+		return err
+	}
+
+	if count := result.Count(failOn...); count > 0 {
+		return cli.Exit(fmt.Sprintf("%d difference(s) of kind %v found", count, failOn), ExitCodeDifferences)
+	}
+
+	return nil
+}
+
+func init() {
+	cmd := &cli.Command{
+		Name:      "diff",
+		Usage:     "compare two json inventories or run reports and show the added, removed and changed resources",
+		ArgsUsage: "<old> <new>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output format of the diff (text, json)",
+				Value: "text",
+			},
+			&cli.StringSliceFlag{
+				Name:  "fail-on",
+				Usage: "kinds of differences that exit with code 2 (added, removed, changed or none)",
+				Value: []string{string(diff.KindAdded), string(diff.KindRemoved), string(diff.KindChanged)},
+			},
+			&cli.StringSliceFlag{
+				Name:  "ignore-property",
+				Usage: "property that is not compared, e.g. LastModifiedAt (can be given multiple times)",
+			},
+		}, global.Flags()...),
+		Before: global.Before,
+		Action: execute,
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -33,6 +33,8 @@ func execute(_ context.Context, _ *cli.Command) error {
 			switch reg.Scope {
 			case azure.TenantScope:
 				clr = color.FgHiGreen
+			case azure.ManagementGroupScope:
+				clr = color.FgHiYellow
 			case azure.SubscriptionScope:
 				clr = color.FgHiBlue
 			case azure.ResourceGroupScope:
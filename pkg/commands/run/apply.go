@@ -0,0 +1,105 @@
+package run
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/azure-nuke/pkg/commands/global"
+	"github.com/ekristen/azure-nuke/pkg/common"
+	"github.com/ekristen/azure-nuke/pkg/plan"
+	"github.com/ekristen/azure-nuke/pkg/report"
+)
+
+func apply(ctx context.Context, cmd *cli.Command) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outputFormat, err := report.ParseFormat(cmd.String("output"))
+	if err != nil {
+		return err
+	}
+
+	logger := configureLogging(cmd, outputFormat)
+
+	planned, err := plan.Load(cmd.String("plan"))
+	if err != nil {
+		return err
+	}
+
+	if planned.TenantID != cmd.String("tenant-id") {
+		return fmt.Errorf("plan was created for tenant %s, not %s", planned.TenantID, cmd.String("tenant-id"))
+	}
+
+	// Note: an empty plan has no resource types, the scanners would otherwise not be limited at all
+	if len(planned.Resources) == 0 {
+		logger.WithField("component", "plan").Info("plan has no resources, nothing to apply")
+		return nil
+	}
+
+	params := newParameters(cmd)
+	params.NoDryRun = true
+
+	inst, err := prepare(ctx, cmd, params, nil, logger)
+	if err != nil {
+		return err
+	}
+
+	inst.prompt.Output = promptOutput(outputFormat)
+
+	planLog := logger.WithField("component", "plan")
+
+	// Note: the prompt is called once before the scan and once after the scan, the queue is only populated for the
+	// second call, which is where the scanned resources are reconciled against the plan before anything is removed.
+	inst.nuke.RegisterPrompt(func() error {
+		inst.protectTags()
+		inst.protectFilteredLocks()
+
+		if inst.nuke.Queue.Total() > 0 {
+			rejected := planned.Reconcile(inst.nuke.Queue, planLog)
+			planLog.Infof("plan reconciled: %d planned, %d rejected", len(planned.Resources), rejected)
+		}
+
+		if err := inst.checkLimits(); err != nil {
+			return err
+		}
+
+		return inst.prompt.Prompt()
+	})
+
+	if err := inst.registerScanners(planned.ResourceTypes()); err != nil {
+		return err
+	}
+
+	runReport := report.New(inst.tenant.ID, false)
+	runReport.Summary.SkippedSubscriptions = inst.tenant.SkippedSubscriptions
+
+	runErr := inst.nuke.Run(ctx)
+
+	if err := writeReport(runReport, inst.nuke, outputFormat, cmd.String("report-file"), runErr); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+func init() {
+	applyFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "plan",
+			Usage:    "path to the plan file created by run --plan-out",
+			Required: true,
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:   "apply",
+		Usage:  "remove only the resources from a plan file that still match what was planned",
+		Flags:  append(append(flags(), applyFlags...), global.Flags()...),
+		Before: global.Before,
+		Action: apply,
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -3,26 +3,27 @@ package run
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"slices"
-	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
 	libconfig "github.com/ekristen/libnuke/pkg/config"
-	"github.com/ekristen/libnuke/pkg/filter"
 	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
 	"github.com/ekristen/libnuke/pkg/registry"
-	"github.com/ekristen/libnuke/pkg/scanner"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/azure-nuke/pkg/azure"
 	"github.com/ekristen/azure-nuke/pkg/commands/global"
 	"github.com/ekristen/azure-nuke/pkg/common"
 	"github.com/ekristen/azure-nuke/pkg/config"
+	"github.com/ekristen/azure-nuke/pkg/metrics"
+	"github.com/ekristen/azure-nuke/pkg/plan"
+	"github.com/ekristen/azure-nuke/pkg/report"
 )
 
 type log2LogrusWriter struct {
@@ -38,10 +39,156 @@ func (w *log2LogrusWriter) Write(b []byte) (int, error) {
 	return n, nil
 }
 
-func execute(ctx context.Context, cmd *cli.Command) error { //nolint:funlen,gocyclo
+func execute(ctx context.Context, cmd *cli.Command) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	outputFormat, err := report.ParseFormat(cmd.String("output"))
+	if err != nil {
+		return err
+	}
+
+	// Note: after a removal the queue only holds finished and failed resources, there is nothing left to plan
+	if cmd.Bool("no-dry-run") && cmd.String("plan-out") != "" {
+		return fmt.Errorf("--plan-out can only be used with a dry run, not with --no-dry-run")
+	}
+
+	logger := configureLogging(cmd, outputFormat)
+
+	params := newParameters(cmd)
+	params.NoDryRun = cmd.Bool("no-dry-run")
+
+	runMetrics, err := startMetrics(ctx, cmd, logger)
+	if err != nil {
+		return err
+	}
+
+	// Note: the outcome is recorded however the run ends, a run that fails before the scan is recorded as failed
+	var inst *instance
+	defer func() {
+		finishMetrics(ctx, cmd, runMetrics, inst, err, logger)
+	}()
+
+	inst, err = prepare(ctx, cmd, params, runMetrics, logger)
+	if err != nil {
+		return err
+	}
+
+	inst.prompt.Output = promptOutput(outputFormat)
+
+	// Note: the prompt is called once before the scan and once after the scan, the queue is only populated for the
+	// second call, the metrics time the phases between the prompts.
+	inst.nuke.RegisterPrompt(func() error {
+		scanned := inst.nuke.Queue.Total() > 0
+
+		inst.protectTags()
+		inst.protectFilteredLocks()
+
+		if scanned {
+			inst.metrics.FinishScan(inst.nuke.Queue)
+		}
+
+		if err := inst.checkLimits(); err != nil {
+			return err
+		}
+
+		if err := inst.prompt.Prompt(); err != nil {
+			return err
+		}
+
+		if scanned {
+			inst.metrics.StartRemoval()
+		} else {
+			inst.metrics.StartScan()
+		}
+
+		return nil
+	})
+
+	if err := inst.registerScanners(nil); err != nil {
+		return err
+	}
+
+	logrus.Debug("running ...")
+
+	runReport := report.New(inst.tenant.ID, !params.NoDryRun)
+	runReport.Summary.SkippedSubscriptions = inst.tenant.SkippedSubscriptions
+
+	runErr := inst.nuke.Run(ctx)
+
+	if !params.NoDryRun && runErr == nil && inst.nuke.Queue != nil {
+		// Note: a dry run does not prompt after the scan, the tag protection and the removal limits are applied here
+		// to match the real run
+		inst.protectTags()
+
+		if err := inst.limits.Check(inst.nuke.Queue); err != nil {
+			runErr = err
+		} else if items := azure.RemovableItems(inst.nuke.Queue); len(items) > 0 {
+			logger.
+				WithField("component", "run").
+				Infof("approval token for removing these %d resources: %s", len(items),
+					azure.ApprovalToken(inst.tenant.ID, items))
+		}
+	}
+
+	if planOut := cmd.String("plan-out"); planOut != "" && runErr == nil {
+		planned := plan.New(inst.tenant.ID, inst.nuke.Queue)
+		if err := planned.Save(planOut); err != nil {
+			return err
+		}
+
+		logger.
+			WithField("component", "run").
+			WithField("path", planOut).
+			Infof("plan with %d resources written", len(planned.Resources))
+	}
+
+	runReport.Summary.LiftedLocks = inst.liftedLocks()
+
+	if err := writeReport(runReport, inst.nuke, outputFormat, cmd.String("report-file"), runErr); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// instance is everything that is prepared to run nuke against a tenant
+type instance struct {
+	nuke   *libnuke.Nuke
+	tenant *azure.Tenant
+	config *config.Config
+	prompt *azure.Prompt
+	locks  *azure.LockReleaser
+	logger *logrus.Logger
+
+	// metrics are the metrics of the run, nil if they are disabled
+	metrics *metrics.Metrics
+
+	tagProtection *azure.TagProtection
+	limits        *azure.RemovalLimits
+
+	tenantID string
+}
+
+// configureLogging configures the standard logger and the logrus logger used throughout the run
+func configureLogging(cmd *cli.Command, outputFormat report.Format) *logrus.Logger {
+	// When the report is written to stdout, the logs have to move out of the way so the output stays parseable
+	return newLogger(outputFormat != report.FormatText && cmd.String("report-file") == "")
+}
+
+// promptOutput returns where the prompt is written to, the prompt never goes to stdout when the output is
+// machine-readable, even with a report file, so that stdout can be consumed by a pipeline.
+func promptOutput(outputFormat report.Format) io.Writer {
+	if outputFormat != report.FormatText {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+// newLogger configures the standard logger and the logrus logger, the logs are written to stderr instead of stdout
+// when stdout is used for machine-readable output.
+func newLogger(stderr bool) *logrus.Logger {
 	// This is to purposefully capture the output from the standard logger that is written to by several
 	// of the azure sdk golang libraries by hashicorp
 	log.SetOutput(&log2LogrusWriter{
@@ -51,32 +198,40 @@ func execute(ctx context.Context, cmd *cli.Command) error { //nolint:funlen,gocy
 	logger := logrus.StandardLogger()
 	logger.SetOutput(os.Stdout)
 
-	logger.Tracef("tenant id: %s", cmd.String("tenant-id"))
-
-	if cmd.String("client-id") == "" &&
-		(cmd.String("client-secret") != "" || cmd.String("client-certificate-file") != "" || cmd.String("client-federated-token-file") != "") {
-		return fmt.Errorf("--client-id is required when using --client-secret, --client-certificate-file, or --client-federated-token-file")
+	if stderr {
+		logger.SetOutput(os.Stderr)
 	}
 
-	authorizers, err := azure.ConfigureAuth(ctx,
-		cmd.String("environment"), cmd.String("tenant-id"), cmd.String("client-id"),
-		cmd.String("client-secret"), cmd.String("client-certificate-file"),
-		cmd.String("client-federated-token-file"))
-	if err != nil {
-		return err
-	}
-
-	logger.Trace("preparing to run nuke")
+	return logger
+}
 
-	params := &libnuke.Parameters{
+// newParameters creates the libnuke parameters from the CLI flags that are common to all commands that remove
+func newParameters(cmd *cli.Command) *libnuke.Parameters {
+	return &libnuke.Parameters{
 		Force:              cmd.Bool("no-prompt"),
 		ForceSleep:         int(cmd.Int("prompt-delay")), //nolint:unconvert
 		Quiet:              cmd.Bool("quiet"),
-		NoDryRun:           cmd.Bool("no-dry-run"),
 		Includes:           cmd.StringSlice("include"),
 		Excludes:           cmd.StringSlice("exclude"),
 		WaitOnDependencies: cmd.Bool("wait-on-dependencies"),
 	}
+}
+
+// prepare configures authentication, parses the configuration, discovers the tenant and sets up the underlying
+// nuke process. It does not register any scanners or the prompt. The metrics are optional.
+func prepare( //nolint:funlen
+	ctx context.Context, cmd *cli.Command, params *libnuke.Parameters, runMetrics *metrics.Metrics,
+	logger *logrus.Logger) (*instance, error) {
+	logger.Tracef("tenant id: %s", cmd.String("tenant-id"))
+
+	authorizers, err := newAuthorizers(ctx, cmd, runMetrics)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizers.LockReleaser = azure.NewLockReleaser(authorizers, cmd.Bool("remove-blocking-locks"))
+
+	logger.Trace("preparing to run nuke")
 
 	parsedConfig, err := config.New(libconfig.Options{
 		Path:         cmd.String("config"),
@@ -85,30 +240,27 @@ func execute(ctx context.Context, cmd *cli.Command) error { //nolint:funlen,gocy
 	})
 	if err != nil {
 		logger.Errorf("Failed to parse config file %s", cmd.String("config"))
-		return err
+		return nil, err
 	}
 
-	tenant, err := azure.NewTenant(ctx,
-		authorizers, cmd.String("tenant-id"), cmd.StringSlice("subscription-id"), parsedConfig.Regions)
+	protectTags, err := azure.ParseProtectTags(parsedConfig.ProtectTags)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	filters, err := parsedConfig.Filters(cmd.String("tenant-id"))
+	tenant, err := discoverTenant(ctx, cmd, authorizers, &azure.TenantOptions{
+		Regions:                parsedConfig.Regions,
+		SubscriptionBlocklist:  parsedConfig.SubscriptionBlocklist,
+		SubscriptionAllowlist:  parsedConfig.Subscriptions(cmd.String("tenant-id")),
+		LookupSubscriptionTags: len(protectTags) > 0,
+	}, logger)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Setup Region Filters as Global Filters
-	if len(filters[filter.Global]) == 0 {
-		filters[filter.Global] = []filter.Filter{}
-	}
-	if !slices.Contains(parsedConfig.Regions, "all") {
-		filters[filter.Global] = append(filters[filter.Global], filter.Filter{
-			Property: "Region",
-			Type:     filter.NotIn,
-			Values:   parsedConfig.Regions,
-		})
+	filters, err := parsedConfig.RunFilters(cmd.String("tenant-id"))
+	if err != nil {
+		return nil, err
 	}
 
 	// Initialize the underlying nuke process
@@ -119,147 +271,215 @@ func execute(ctx context.Context, cmd *cli.Command) error { //nolint:funlen,gocy
 
 	n.RegisterVersion(fmt.Sprintf("> %s", common.AppVersion.String()))
 
-	p := &azure.Prompt{Parameters: params, Tenant: tenant}
-	n.RegisterPrompt(p.Prompt)
-
-	tenantConfig := parsedConfig.Accounts[cmd.String("tenant-id")]
-	tenantResourceTypes := types.ResolveResourceTypes(
-		registry.GetNamesForScope(azure.TenantScope),
-		[]types.Collection{
-			n.Parameters.Includes,
-			parsedConfig.ResourceTypes.GetIncludes(),
-			tenantConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			n.Parameters.Excludes,
-			parsedConfig.ResourceTypes.Excludes,
-			tenantConfig.ResourceTypes.Excludes,
-		},
-		nil,
-		nil,
-	)
-
-	subResourceTypes := types.ResolveResourceTypes(
-		registry.GetNamesForScope(azure.SubscriptionScope),
-		[]types.Collection{
-			n.Parameters.Includes,
-			parsedConfig.ResourceTypes.GetIncludes(),
-			tenantConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			n.Parameters.Excludes,
-			parsedConfig.ResourceTypes.Excludes,
-			tenantConfig.ResourceTypes.Excludes,
-		},
-		nil,
-		nil,
-	)
-
-	rgResourceTypes := types.ResolveResourceTypes(
-		registry.GetNamesForScope(azure.ResourceGroupScope),
-		[]types.Collection{
-			n.Parameters.Includes,
-			parsedConfig.ResourceTypes.GetIncludes(),
-			tenantConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			n.Parameters.Excludes,
-			parsedConfig.ResourceTypes.Excludes,
-			tenantConfig.ResourceTypes.Excludes,
-		},
-		nil,
-		nil,
-	)
-
-	if slices.Contains(parsedConfig.Regions, "global") || slices.Contains(parsedConfig.Regions, "all") {
-		tenantScanner, scanErr := scanner.New(&scanner.Config{
-			Owner:         "tenant",
-			ResourceTypes: tenantResourceTypes,
-			Opts: &azure.ListerOpts{
-				Authorizers: authorizers,
-				TenantID:    tenant.ID,
-			},
-			Logger: logger,
-		})
-		if scanErr != nil {
-			return scanErr
+	return &instance{
+		nuke:   n,
+		tenant: tenant,
+		config: parsedConfig,
+		prompt: &azure.Prompt{
+			Parameters:              params,
+			Tenant:                  tenant,
+			ConfirmSubscriptionName: cmd.Bool("confirm-subscription-name"),
+			ApprovalToken:           cmd.String("approval-token"),
+		},
+		locks:    authorizers.LockReleaser,
+		logger:   logger,
+		metrics:  runMetrics,
+		tenantID: cmd.String("tenant-id"),
+
+		tagProtection: azure.NewTagProtection(protectTags, tenant),
+		limits: &azure.RemovalLimits{
+			MaxRemovals:                cmd.Int("max-removals"),
+			MaxRemovalPercent:          cmd.Float("max-removal-percent"),
+			MaxRemovalsPerSubscription: parsedConfig.MaxRemovalsPerSubscription,
+		},
+	}, nil
+}
+
+// newAuthorizers configures the authentication and the throttle shared by all clients from the CLI flags, the
+// responses of the APIs are counted by the metrics, if provided
+func newAuthorizers(ctx context.Context, cmd *cli.Command, runMetrics *metrics.Metrics) (*azure.Authorizers, error) {
+	authOpts := &azure.AuthOptions{
+		Environment:             cmd.String("environment"),
+		EnvironmentMetadataURL:  cmd.String("environment-metadata-url"),
+		TenantID:                cmd.String("tenant-id"),
+		ClientID:                cmd.String("client-id"),
+		ClientSecret:            cmd.String("client-secret"),
+		ClientCertFile:          cmd.String("client-certificate-file"),
+		ClientFedTokenFile:      cmd.String("client-federated-token-file"),
+		UseManagedIdentity:      cmd.Bool("use-managed-identity"),
+		ManagedIdentityClientID: cmd.String("managed-identity-client-id"),
+		UseDefaultCredential:    cmd.Bool("use-default-credential"),
+	}
+
+	if !authOpts.UseManagedIdentity && !authOpts.UseDefaultCredential && authOpts.ClientID == "" &&
+		(authOpts.ClientSecret != "" || authOpts.ClientCertFile != "" || authOpts.ClientFedTokenFile != "") {
+		return nil, fmt.Errorf("--client-id is required when using --client-secret, --client-certificate-file, or --client-federated-token-file")
+	}
+
+	authorizers, err := azure.ConfigureAuth(ctx, authOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	throttleOpts := &azure.ThrottleOptions{
+		RequestsPerSecond: cmd.Float("max-requests-per-second"),
+		MaxRetries:        cmd.Int("max-retries"),
+		RetryBudget:       cmd.Int("retry-budget"),
+		MaxRetryDelay:     cmd.Duration("max-retry-delay"),
+	}
+
+	// Note: a nil *metrics.Metrics must not be set as observer, the interface would not be nil
+	if runMetrics != nil {
+		throttleOpts.Observer = runMetrics
+	}
+
+	authorizers.Throttle = azure.NewThrottle(throttleOpts)
+
+	return authorizers, nil
+}
+
+// discoverTenant discovers the subscriptions, management groups and resource groups of the tenant, the options are
+// completed from the CLI flags.
+func discoverTenant(ctx context.Context, cmd *cli.Command, authorizers *azure.Authorizers, opts *azure.TenantOptions,
+	logger *logrus.Logger) (*azure.Tenant, error) {
+	opts.TenantID = cmd.String("tenant-id")
+	opts.SubscriptionIDs = cmd.StringSlice("subscription-id")
+	opts.ManagementGroupIDs = cmd.StringSlice("management-group")
+	opts.AllManagementGroups = cmd.Bool("all-management-groups")
+	opts.DiscoveryTimeout = cmd.Duration("discovery-timeout")
+	opts.DiscoveryConcurrency = cmd.Int("discovery-concurrency")
+
+	tenant, err := azure.NewTenant(ctx, authorizers, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for subscriptionID, reason := range tenant.SkippedSubscriptions {
+		logger.WithField("subscription_id", subscriptionID).Warnf("subscription skipped: %s", reason)
+	}
+
+	return tenant, nil
+}
+
+// registerScanners resolves the resource types for every scope and registers the scanners with the nuke process. If
+// limit is provided the resource types are restricted to those in the limit.
+func (i *instance) registerScanners(limit types.Collection) error {
+	resourceTypes := make(map[registry.Scope]types.Collection)
+	for _, scope := range []registry.Scope{
+		azure.TenantScope, azure.ManagementGroupScope, azure.SubscriptionScope, azure.ResourceGroupScope,
+	} {
+		resourceTypes[scope] = i.config.ResolveResourceTypes(
+			i.tenantID, scope, i.nuke.Parameters.Includes, i.nuke.Parameters.Excludes)
+
+		if limit != nil {
+			resourceTypes[scope] = resourceTypes[scope].Intersect(limit)
 		}
+	}
 
-		if err := n.RegisterScanner(azure.TenantScope, tenantScanner); err != nil {
-			return err
+	return azure.RegisterScanners(i.nuke.RegisterScanner, &azure.ScannerOptions{
+		Tenant:        i.tenant,
+		Regions:       i.config.Regions,
+		ResourceTypes: resourceTypes,
+		Logger:        i.logger,
+		TagProtection: i.tagProtection,
+	})
+}
+
+// protectTags filters the resources within protected resource groups and subscriptions that are still queued for
+// removal. The resources are usually filtered when they are listed already, this covers the resources that do not
+// apply the tag protection themselves.
+func (i *instance) protectTags() {
+	for _, item := range i.nuke.Queue.GetItems() {
+		if !i.tagProtection.Protect(item) {
+			continue
 		}
 
-		logger.
-			WithField("component", "run").
-			WithField("scope", "tenant").
-			Debug("registering scanner")
-		for _, subscriptionID := range tenant.SubscriptionIds {
-			logger.
-				WithField("component", "run").
-				WithField("scope", "subscription").
-				WithField("subscription_id", subscriptionID).
-				Debug("registering scanner")
-
-			parts := strings.Split(subscriptionID, "-")
-			subScanner, scanErr := scanner.New(&scanner.Config{
-				Owner:         fmt.Sprintf("sub/%s", parts[:1][0]),
-				ResourceTypes: subResourceTypes,
-				Opts: &azure.ListerOpts{
-					Authorizers:    tenant.Authorizers,
-					TenantID:       tenant.ID,
-					SubscriptionID: subscriptionID,
-					Regions:        parsedConfig.Regions,
-				},
-				Logger: logger,
-			})
-			if scanErr != nil {
-				return scanErr
-			}
-
-			if err := n.RegisterScanner(azure.SubscriptionScope, subScanner); err != nil {
-				return err
-			}
+		i.logger.
+			WithField("type", item.Type).
+			WithField("owner", item.Owner).
+			WithField("name", report.NewItem(item).Name).
+			Warnf("refusing to remove resource: %s", item.GetReason())
+	}
+}
+
+// protectFilteredLocks prevents the management locks that are filtered by the configuration from being removed
+// when they block the removal of another resource.
+func (i *instance) protectFilteredLocks() {
+	for _, item := range i.nuke.Queue.GetItems() {
+		if item.Type != "ManagementLock" && item.Type != "SubscriptionManagementLock" {
+			continue
+		}
+
+		if item.GetState() != queue.ItemStateFiltered {
+			continue
+		}
+
+		scope, _ := item.GetProperty("Scope")
+		name, _ := item.GetProperty("Name")
+		if scope != "" && name != "" {
+			i.locks.Protect(azure.LockID(scope, name))
 		}
 	}
+}
 
-	for subscriptionID, resourceGroups := range tenant.ResourceGroups {
-		for _, rg := range resourceGroups {
-			logger.
-				WithField("component", "run").
-				WithField("scope", "resource-group").
-				WithField("subscription_id", subscriptionID).
-				WithField("resource_group", rg).
-				Debug("registering scanner")
-
-			rgScanner, scanErr := scanner.New(&scanner.Config{
-				Owner:         fmt.Sprintf("sub/%s/rg/%s", subscriptionID, rg),
-				ResourceTypes: rgResourceTypes,
-				Opts: &azure.ListerOpts{
-					Authorizers:    tenant.Authorizers,
-					TenantID:       tenant.ID,
-					SubscriptionID: subscriptionID,
-					ResourceGroup:  rg,
-					Regions:        parsedConfig.Regions,
-				},
-				Logger: logger,
-			})
-			if scanErr != nil {
-				return scanErr
-			}
-
-			if err := n.RegisterScanner(azure.ResourceGroupScope, rgScanner); err != nil {
-				return err
-			}
+// checkLimits aborts the run when more resources would be removed than the removal limits allow, it only applies once
+// the resources have been scanned. The prompt is given the scanned resources to show what is about to be removed.
+func (i *instance) checkLimits() error {
+	if i.nuke.Queue.Total() == 0 {
+		return nil
+	}
+
+	i.prompt.Queue = i.nuke.Queue
+
+	return i.limits.Check(i.nuke.Queue)
+}
+
+// liftedLocks returns the management locks that were removed during the run for the report
+func (i *instance) liftedLocks() []*report.LiftedLock {
+	lifted := make([]*report.LiftedLock, 0)
+	for _, l := range i.locks.Lifted() {
+		lifted = append(lifted, &report.LiftedLock{
+			ResourceID: l.ResourceID,
+			LockID:     l.ID,
+			Name:       l.Name,
+			Level:      l.Level,
+			Scope:      l.Scope,
+			Restored:   l.Restored,
+		})
+	}
+
+	return lifted
+}
+
+// writeReport writes the machine-readable report of the run, either to the report file or to stdout.
+func writeReport(runReport *report.Report, n *libnuke.Nuke, format report.Format, path string, runErr error) error {
+	if format == report.FormatText {
+		if path == "" {
+			return nil
 		}
+
+		format = report.FormatJSON
 	}
 
-	logrus.Debug("running ...")
+	runReport.AddQueue(n.Queue)
+	runReport.Finish(runErr)
 
-	return n.Run(ctx)
+	if path == "" {
+		return runReport.Write(os.Stdout, format)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return runReport.Write(f, format)
 }
 
-func init() {
-	flags := []cli.Flag{
+// flags returns the flags shared by all commands that run nuke against a tenant
+func flags() []cli.Flag {
+	return append([]cli.Flag{
 		&cli.StringFlag{
 			Name:  "config",
 			Usage: "path to config file",
@@ -278,10 +498,6 @@ func init() {
 			Aliases: []string{"q"},
 			Usage:   "hide filtered messages",
 		},
-		&cli.BoolFlag{
-			Name:  "no-dry-run",
-			Usage: "actually run the removal of the resources after discovery",
-		},
 		&cli.BoolFlag{
 			Name:    "no-prompt",
 			Usage:   "disable prompting for verification to run",
@@ -293,6 +509,15 @@ func init() {
 			Value:   10,
 			Aliases: []string{"force-sleep"},
 		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "output format of the run report (text, json, ndjson)",
+			Value: "text",
+		},
+		&cli.StringFlag{
+			Name:  "report-file",
+			Usage: "write the run report to this file instead of stdout (defaults to json when --output is text)",
+		},
 		&cli.BoolFlag{
 			Name:  "wait-on-dependencies",
 			Usage: "wait for dependent resources to be deleted before deleting resources that depend on them",
@@ -302,12 +527,47 @@ func init() {
 			Usage:   "enable experimental behaviors that may not be fully tested or supported",
 			Sources: cli.EnvVars("AZURE_NUKE_FEATURE_FLAGS"),
 		},
+		&cli.BoolFlag{
+			Name:  "confirm-subscription-name",
+			Usage: "enter the name of the subscription instead of the tenant ID to confirm, when only one subscription is targeted",
+		},
+		&cli.StringFlag{
+			Name:    "approval-token",
+			Usage:   "approve the removal without prompting, the token is shown by a dry run and only matches the same resources",
+			Sources: cli.EnvVars("AZURE_NUKE_APPROVAL_TOKEN"),
+		},
+		&cli.IntFlag{
+			Name:    "max-removals",
+			Usage:   "abort the run before anything is removed if more resources would be removed (0 disables the limit)",
+			Sources: cli.EnvVars("AZURE_NUKE_MAX_REMOVALS"),
+		},
+		&cli.FloatFlag{
+			Name:    "max-removal-percent",
+			Usage:   "abort the run before anything is removed if a larger percentage of the scanned resources would be removed",
+			Sources: cli.EnvVars("AZURE_NUKE_MAX_REMOVAL_PERCENT"),
+		},
+		&cli.BoolFlag{
+			Name:    "remove-blocking-locks",
+			Usage:   "remove the management locks that block the removal of a resource (unless filtered by the config)",
+			Sources: cli.EnvVars("AZURE_NUKE_REMOVE_BLOCKING_LOCKS"),
+		},
+	}, tenantFlags()...)
+}
+
+// tenantFlags returns the flags for authenticating against a tenant and discovering its resources
+func tenantFlags() []cli.Flag {
+	return []cli.Flag{
 		&cli.StringFlag{
 			Name:    "environment",
-			Usage:   "Azure Environment",
+			Usage:   "Azure Environment (global, usgovernment, dod, china) or the name of the environment of the metadata url",
 			Sources: cli.EnvVars("AZURE_ENVIRONMENT"),
 			Value:   "global",
 		},
+		&cli.StringFlag{
+			Name:    "environment-metadata-url",
+			Usage:   "load the environment from this ARM metadata endpoint, e.g. for Azure Stack Hub",
+			Sources: cli.EnvVars("AZURE_ENVIRONMENT_METADATA_URL"),
+		},
 		&cli.StringFlag{
 			Name:     "tenant-id",
 			Usage:    "the tenant-id to nuke",
@@ -320,6 +580,15 @@ func init() {
 			Sources:  cli.EnvVars("AZURE_SUBSCRIPTION_ID"),
 			Required: false,
 		},
+		&cli.StringSliceFlag{
+			Name:    "management-group",
+			Usage:   "the management-group to nuke (this selects the subscriptions and management groups below it)",
+			Sources: cli.EnvVars("AZURE_MANAGEMENT_GROUP_ID"),
+		},
+		&cli.BoolFlag{
+			Name:  "all-management-groups",
+			Usage: "scan all management groups visible to the credentials, including the tenant root group, when no management-group is given",
+		},
 		&cli.StringFlag{
 			Name:    "client-id",
 			Usage:   "the client-id to use for authentication (optional when using Azure CLI auth)",
@@ -340,13 +609,80 @@ func init() {
 			Usage:   "the client-federated-token-file to use for authentication",
 			Sources: cli.EnvVars("AZURE_FEDERATED_TOKEN_FILE"),
 		},
+		&cli.BoolFlag{
+			Name:    "use-managed-identity",
+			Usage:   "authenticate using the managed identity of the host (e.g. Container Instances, AKS, VMs)",
+			Sources: cli.EnvVars("AZURE_USE_MANAGED_IDENTITY"),
+		},
+		&cli.StringFlag{
+			Name:    "managed-identity-client-id",
+			Usage:   "the client-id of the user-assigned managed identity to use (default: system-assigned)",
+			Sources: cli.EnvVars("AZURE_MANAGED_IDENTITY_CLIENT_ID"),
+		},
+		&cli.BoolFlag{
+			Name:    "use-default-credential",
+			Usage:   "authenticate using the default credential chain (environment, workload identity, managed identity, azure cli)",
+			Sources: cli.EnvVars("AZURE_USE_DEFAULT_CREDENTIAL"),
+		},
+		&cli.DurationFlag{
+			Name:  "discovery-timeout",
+			Usage: "the maximum time the discovery of subscriptions and resource groups may take",
+			Value: 5 * time.Minute,
+		},
+		&cli.IntFlag{
+			Name:  "discovery-concurrency",
+			Usage: "the number of subscriptions whose resource groups are discovered in parallel",
+			Value: 10,
+		},
+		&cli.FloatFlag{
+			Name:  "max-requests-per-second",
+			Usage: "the maximum number of requests per second to each API host (0 for unlimited)",
+		},
+		&cli.IntFlag{
+			Name:  "max-retries",
+			Usage: "the maximum number of times a throttled request is retried",
+			Value: 5,
+		},
+		&cli.IntFlag{
+			Name:  "retry-budget",
+			Usage: "the total number of retries of throttled requests for the whole run (0 for unlimited)",
+			Value: 500,
+		},
+		&cli.DurationFlag{
+			Name:  "max-retry-delay",
+			Usage: "the maximum time to wait before retrying a throttled request",
+			Value: time.Minute,
+		},
+	}
+}
+
+func init() {
+	runFlags := []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "no-dry-run",
+			Usage: "actually run the removal of the resources after discovery",
+		},
+		&cli.StringFlag{
+			Name:  "plan-out",
+			Usage: "write the resources that would be removed by a dry run to this plan file, to be used with the apply command",
+		},
+		&cli.StringFlag{
+			Name:    "metrics-listen",
+			Usage:   "serve prometheus metrics on /metrics of this address during the run, e.g. :9090",
+			Sources: cli.EnvVars("AZURE_NUKE_METRICS_LISTEN"),
+		},
+		&cli.StringFlag{
+			Name:    "metrics-pushgateway",
+			Usage:   "push the prometheus metrics of the run to this pushgateway url once the run is finished",
+			Sources: cli.EnvVars("AZURE_NUKE_METRICS_PUSHGATEWAY"),
+		},
 	}
 
 	cmd := &cli.Command{
 		Name:    "run",
 		Aliases: []string{"nuke"},
 		Usage:   "run nuke against an azure tenant to remove all configured resources",
-		Flags:   append(flags, global.Flags()...),
+		Flags:   append(append(flags(), runFlags...), global.Flags()...),
 		Before:  global.Before,
 		Action:  execute,
 	}
@@ -0,0 +1,162 @@
+package run
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/commands/global"
+	"github.com/ekristen/azure-nuke/pkg/common"
+	"github.com/ekristen/azure-nuke/pkg/config"
+	"github.com/ekristen/azure-nuke/pkg/inventory"
+)
+
+// inventoryCommand lists all resources of the tenant. It uses the same scanners as a run, but does not require a
+// configuration and never filters, prompts or removes anything.
+func inventoryCommand(ctx context.Context, cmd *cli.Command) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	format, err := inventory.ParseFormat(cmd.String("format"))
+	if err != nil {
+		return err
+	}
+
+	// Note: the logs never share stdout with the inventory, including the table
+	logger := newLogger(cmd.String("output-file") == "")
+
+	authorizers, err := newAuthorizers(ctx, cmd, nil)
+	if err != nil {
+		return err
+	}
+
+	regions := cmd.StringSlice("region")
+
+	tenant, err := discoverTenant(ctx, cmd, authorizers, &azure.TenantOptions{Regions: regions}, logger)
+	if err != nil {
+		return err
+	}
+
+	// Note: an empty configuration resolves to every registered resource type that is not opt-in
+	emptyConfig := &config.Config{}
+
+	resourceTypes := make(map[registry.Scope]types.Collection)
+	for _, scope := range []registry.Scope{
+		azure.TenantScope, azure.ManagementGroupScope, azure.SubscriptionScope, azure.ResourceGroupScope,
+	} {
+		resourceTypes[scope] = emptyConfig.ResolveResourceTypes(
+			"", scope, cmd.StringSlice("include"), cmd.StringSlice("exclude"))
+	}
+
+	scanners := make([]*scanner.Scanner, 0)
+	if err := azure.RegisterScanners(func(_ registry.Scope, s *scanner.Scanner) error {
+		scanners = append(scanners, s)
+		return nil
+	}, &azure.ScannerOptions{
+		Tenant:        tenant,
+		Regions:       regions,
+		ResourceTypes: resourceTypes,
+		Logger:        logger,
+	}); err != nil {
+		return err
+	}
+
+	inv := inventory.New(tenant.ID)
+
+	for _, s := range scanners {
+		if err := runScanner(ctx, s, func(item *queue.Item) {
+			if r, ok := item.Resource.(regionGetter); ok && !slices.Contains(regions, "all") &&
+				!slices.Contains(regions, r.GetRegion()) {
+				return
+			}
+
+			inv.Add(item)
+		}); err != nil {
+			return err
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if path := cmd.String("output-file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	return inv.Write(w, format)
+}
+
+// runScanner runs the scanner and passes every item it finds to the handler. The items are drained while the scanner
+// is running, so it never blocks on a full channel.
+func runScanner(ctx context.Context, s *scanner.Scanner, handle func(*queue.Item)) error {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for item := range s.Items {
+			handle(item)
+		}
+	}()
+
+	// Note: the scanner only closes the channel once all listers completed, it is left open when the run is aborted
+	if err := s.Run(ctx); err != nil {
+		return err
+	}
+
+	<-done
+
+	return nil
+}
+
+// regionGetter is implemented by the resources that are located in a region
+type regionGetter interface {
+	GetRegion() string
+}
+
+func init() {
+	cmd := &cli.Command{
+		Name:  "inventory",
+		Usage: "list all resources of the tenant without filtering, prompting or removing anything",
+		Flags: append(append([]cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "only include this specific resource",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "exclude this specific resource",
+			},
+			&cli.StringSliceFlag{
+				Name:  "region",
+				Usage: "only list resources in these regions (use global for resources without a region)",
+				Value: []string{"all"},
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "output format of the inventory (table, csv, json)",
+				Value: "table",
+			},
+			&cli.StringFlag{
+				Name:  "output-file",
+				Usage: "write the inventory to this file instead of stdout",
+			},
+		}, tenantFlags()...), global.Flags()...),
+		Before: global.Before,
+		Action: inventoryCommand,
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -0,0 +1,71 @@
+package run
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/azure-nuke/pkg/metrics"
+)
+
+// metricsJob is the job the metrics are pushed to the Pushgateway as
+const metricsJob = "azure-nuke"
+
+// startMetrics creates the metrics of the run if they are served or pushed and starts serving them, it returns nil
+// when metrics are disabled.
+func startMetrics(ctx context.Context, cmd *cli.Command, logger *logrus.Logger) (*metrics.Metrics, error) {
+	listen := cmd.String("metrics-listen")
+	if listen == "" && cmd.String("metrics-pushgateway") == "" {
+		return nil, nil
+	}
+
+	runMetrics := metrics.New()
+
+	if listen != "" {
+		addr, err := runMetrics.Serve(ctx, listen)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.
+			WithField("component", "metrics").
+			WithField("address", addr).
+			Info("serving metrics on /metrics")
+	}
+
+	return runMetrics, nil
+}
+
+// finishMetrics records the outcome of the run and pushes the metrics to the Pushgateway, if configured. The instance
+// is nil if the run failed before it was prepared. A failed push does not fail the run.
+func finishMetrics(ctx context.Context, cmd *cli.Command, runMetrics *metrics.Metrics, inst *instance, runErr error,
+	logger *logrus.Logger) {
+	if runMetrics == nil {
+		return
+	}
+
+	tenantID := cmd.String("tenant-id")
+
+	var q *queue.Queue
+	if inst != nil {
+		q = inst.nuke.Queue
+		tenantID = inst.tenant.ID
+	}
+
+	runMetrics.Finish(q, runErr)
+
+	url := cmd.String("metrics-pushgateway")
+	if url == "" {
+		return
+	}
+
+	if err := runMetrics.Push(ctx, url, metricsJob, tenantID); err != nil {
+		logger.
+			WithField("component", "metrics").
+			WithError(err).
+			Warn("unable to push metrics to the pushgateway")
+	}
+}
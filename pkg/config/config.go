@@ -2,10 +2,20 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
 )
 
 // New creates a new extended configuration from a file. This is necessary because we are extended the default
@@ -65,4 +75,190 @@ type Config struct {
 	// nuking your production account.
 	// Deprecated: Use Blocklist instead. Will be removed in 2.x
 	TenantBlocklist []string `yaml:"tenant-blocklist"`
+
+	// SubscriptionBlocklist is a list of subscription IDs, names or name globs that must never be scanned. Requesting
+	// one of them explicitly fails the run.
+	SubscriptionBlocklist []string `yaml:"subscription-blocklist"`
+
+	// ProtectTags are the tags, either `key` or `key=value`, that protect a resource from being removed. A protect tag
+	// on a resource group or subscription protects everything within it.
+	ProtectTags []string `yaml:"protect-tags"`
+
+	// MaxRemovalsPerSubscription is the maximum number of resources that may be removed from a single subscription,
+	// the run is aborted before anything is removed if it is exceeded. Zero disables the limit.
+	MaxRemovalsPerSubscription int `yaml:"max-removals-per-subscription"`
+
+	// AccountSubscriptions are the subscription IDs, names or name globs that are allowed per account. They are set
+	// from the `subscriptions` key of an account, which is not part of the libnuke account configuration.
+	AccountSubscriptions map[string][]string `yaml:"-"`
+}
+
+// accountExtension are the keys of an account that are specific to this tool
+type accountExtension struct {
+	Subscriptions []string `yaml:"subscriptions"`
+}
+
+// extensions are the structs of libnuke whose keys are extended by this tool, they are loaded separately because they
+// cannot be embedded in the libnuke structs.
+var extensions = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(config.Account{}): reflect.TypeOf(accountExtension{}),
+}
+
+// Load loads the extended configuration from a file, including the keys of the accounts that are not part of the
+// libnuke account configuration.
+func (c *Config) Load(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if err := yaml.Unmarshal(raw, c); err != nil {
+		return err
+	}
+
+	var extended struct {
+		Accounts map[string]*accountExtension `yaml:"accounts"`
+		Tenants  map[string]*accountExtension `yaml:"tenants"`
+	}
+	if err := yaml.Unmarshal(raw, &extended); err != nil {
+		return err
+	}
+
+	for _, accounts := range []map[string]*accountExtension{extended.Accounts, extended.Tenants} {
+		for id, account := range accounts {
+			if account == nil || len(account.Subscriptions) == 0 {
+				continue
+			}
+
+			if c.AccountSubscriptions == nil {
+				c.AccountSubscriptions = make(map[string][]string)
+			}
+
+			c.AccountSubscriptions[id] = account.Subscriptions
+		}
+	}
+
+	return nil
+}
+
+// Subscriptions returns the subscriptions that are allowed for the account, if empty all subscriptions are allowed
+func (c *Config) Subscriptions(accountID string) []string {
+	return c.AccountSubscriptions[accountID]
+}
+
+// RunFilters returns the filters that a run applies to the account: the filters of the account merged with the
+// filters of its presets, with deprecated resource types resolved to their replacements, plus the global filters of
+// the regions and the protect tags.
+func (c *Config) RunFilters(accountID string) (filter.Filters, error) {
+	// Note: Filters of libnuke appends the filters of the presets to the filters of the account, it is given a copy
+	// of the account so that the configuration stays unchanged
+	cfg := c.Config
+	if account := c.Accounts[accountID]; account != nil {
+		accountCopy := *account
+		accountCopy.Filters = filter.Filters{}
+		for resourceType, resourceFilters := range account.Filters {
+			accountCopy.Filters[resourceType] = slices.Clone(resourceFilters)
+		}
+
+		cfg.Accounts = map[string]*config.Account{accountID: &accountCopy}
+	}
+
+	accountFilters, err := cfg.Filters(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	protectTags, err := azure.ParseProtectTags(c.ProtectTags)
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: libnuke only resolves the deprecated resource types of the account filters, not those of the presets, the
+	// filters of deprecated resource types are added after the others to keep the account filters first
+	filters := filter.Filters{}
+	for resourceType, resourceFilters := range accountFilters {
+		if _, ok := c.Deprecations[resourceType]; !ok {
+			filters[resourceType] = append(filters[resourceType], resourceFilters...)
+		}
+	}
+
+	for resourceType, resourceFilters := range accountFilters {
+		if replacement, ok := c.Deprecations[resourceType]; ok {
+			filters[replacement] = append(filters[replacement], resourceFilters...)
+		}
+	}
+
+	for _, global := range c.globalFilters(protectTags) {
+		filters[filter.Global] = append(filters[filter.Global], global.Filter)
+	}
+
+	return filters, nil
+}
+
+// sourcedFilter is a filter together with where it was defined
+type sourcedFilter struct {
+	filter.Filter
+	Source string
+}
+
+// globalFilters returns the global filters that a run adds for the regions and the protect tags
+func (c *Config) globalFilters(protectTags []azure.ProtectTag) []sourcedFilter {
+	filters := make([]sourcedFilter, 0)
+
+	if !slices.Contains(c.Regions, "all") {
+		filters = append(filters, sourcedFilter{
+			Filter: filter.Filter{Property: "Region", Type: filter.NotIn, Values: c.Regions},
+			Source: "regions",
+		})
+	}
+
+	// Note: the tags inherited from resource groups and subscriptions are applied by the tag protection of the run
+	for _, tag := range protectTags {
+		filters = append(filters, sourcedFilter{Filter: tag.Filter(), Source: "protect-tags"})
+	}
+
+	return filters
+}
+
+// ResolveResourceTypes resolves the resource types registered for the given scope against the includes and excludes
+// provided on the CLI, the global configuration and the configuration of the account.
+func (c *Config) ResolveResourceTypes(
+	accountID string, scope registry.Scope, includes, excludes types.Collection) types.Collection {
+	accountConfig := c.Accounts[accountID]
+	if accountConfig == nil {
+		accountConfig = &config.Account{}
+	}
+
+	includeCollections := []types.Collection{
+		includes,
+		c.ResourceTypes.GetIncludes(),
+		accountConfig.ResourceTypes.GetIncludes(),
+	}
+
+	resourceTypes := types.ResolveResourceTypes(
+		registry.GetNamesForScope(scope),
+		includeCollections,
+		[]types.Collection{
+			excludes,
+			c.ResourceTypes.Excludes,
+			accountConfig.ResourceTypes.Excludes,
+		},
+		nil,
+		nil,
+	)
+
+	// Opt-in resource types are dropped unless they were explicitly included
+	return slices.DeleteFunc(resourceTypes, func(name string) bool {
+		if !azure.IsOptIn(name) {
+			return false
+		}
+
+		for _, included := range includeCollections {
+			if slices.Contains(included, name) {
+				return false
+			}
+		}
+
+		return true
+	})
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"io"
 	"testing"
 
@@ -9,8 +10,12 @@ import (
 
 	libconfig "github.com/ekristen/libnuke/pkg/config"
 	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
 )
 
 func TestLoadExampleConfig(t *testing.T) {
@@ -67,3 +72,53 @@ func TestLoadExampleConfig(t *testing.T) {
 
 	assert.Equal(t, expect, *config)
 }
+
+type testLister struct{}
+
+func (l testLister) List(_ context.Context, _ interface{}) ([]resource.Resource, error) {
+	return nil, nil
+}
+
+func TestResolveResourceTypesOptIn(t *testing.T) {
+	scope := registry.Scope("test-opt-in")
+	registry.Register(&registry.Registration{Name: "TestRegularType", Scope: scope, Lister: testLister{}})
+	registry.Register(&registry.Registration{Name: "TestOptInType", Scope: scope, Lister: testLister{}})
+	azure.RegisterOptIn("TestOptInType")
+
+	c := &Config{}
+
+	assert.Equal(t, types.Collection{"TestRegularType"}, c.ResolveResourceTypes("account", scope, nil, nil))
+	assert.Equal(t, types.Collection{"TestOptInType"},
+		c.ResolveResourceTypes("account", scope, types.Collection{"TestOptInType"}, nil))
+
+	c.ResourceTypes.Includes = types.Collection{"TestOptInType", "TestRegularType"}
+	assert.ElementsMatch(t, types.Collection{"TestOptInType", "TestRegularType"},
+		c.ResolveResourceTypes("account", scope, nil, nil))
+}
+
+func TestLoadSubscriptions(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/subscriptions-config.yaml",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"production", "5f9f2a6e-1c56-4c43-9d9b-52c3d0f6c9a1"}, config.SubscriptionBlocklist)
+	assert.Equal(t, []string{"nuke-protect", "env=production"}, config.ProtectTags)
+	assert.Equal(t, 250, config.MaxRemovalsPerSubscription)
+	assert.Equal(t, []string{"sandbox-*", "0b7c3ad0-7b1f-4a3e-8c5e-9f1c2a3b4c5d"},
+		config.Subscriptions("efda01a1-e2e4-4024-89f0-eb29793c605b"))
+	assert.Empty(t, config.Subscriptions("c3f4a0d2-7c1e-4b0a-9f8e-1a2b3c4d5e6f"))
+	assert.Len(t, config.Accounts, 2)
+
+	issues, err := Validate("testdata/subscriptions-config.yaml")
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+}
+
+func TestLoadDeprecatedKeys(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/deprecated-keys-config.yaml",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1234567890"}, config.Blocklist)
+}
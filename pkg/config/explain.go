@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+// Explain writes how the configuration applies to the given tenant: whether it can be nuked at all, the presets that
+// apply, the resource types that are scanned per scope and the filters after the presets have been merged.
+func (c *Config) Explain(w io.Writer, accountID string, includes, excludes types.Collection) error {
+	e := &explainer{w: w}
+
+	e.printf("Tenant: %s\n", accountID)
+
+	account, configured := c.Accounts[accountID]
+	switch {
+	case c.InBlocklist(accountID):
+		e.printf("Status: blocklisted, a run against this tenant is refused\n")
+	case !configured:
+		e.printf("Status: not configured, a run against this tenant is refused\n")
+	default:
+		e.printf("Status: configured\n")
+	}
+
+	regions := c.Regions
+	if len(regions) == 0 {
+		regions = []string{"(none)"}
+	}
+	e.printf("Regions: %s\n", strings.Join(regions, ", "))
+
+	presets := make([]string, 0)
+	if account != nil {
+		for _, name := range account.Presets {
+			if _, ok := c.Presets[name]; !ok {
+				name += " (undefined)"
+			}
+
+			presets = append(presets, name)
+		}
+	}
+	if len(presets) == 0 {
+		presets = []string{"(none)"}
+	}
+	e.printf("Presets: %s\n", strings.Join(presets, ", "))
+
+	subscriptions := c.Subscriptions(accountID)
+	if len(subscriptions) == 0 {
+		subscriptions = []string{"(all)"}
+	}
+	e.printf("Subscriptions: %s\n", strings.Join(subscriptions, ", "))
+
+	if len(c.SubscriptionBlocklist) > 0 {
+		e.printf("Subscription Blocklist: %s\n", strings.Join(c.SubscriptionBlocklist, ", "))
+	}
+
+	if len(c.ProtectTags) > 0 {
+		e.printf("Protect Tags: %s (including resource groups and subscriptions)\n", strings.Join(c.ProtectTags, ", "))
+	}
+
+	if c.MaxRemovalsPerSubscription > 0 {
+		e.printf("Max Removals Per Subscription: %d\n", c.MaxRemovalsPerSubscription)
+	}
+
+	e.printf("\nResource Types\n")
+	for _, scope := range []registry.Scope{
+		azure.TenantScope, azure.ManagementGroupScope, azure.SubscriptionScope, azure.ResourceGroupScope,
+	} {
+		resourceTypes := c.ResolveResourceTypes(accountID, scope, includes, excludes)
+		sort.Strings(resourceTypes)
+
+		e.printf("  %s (%d)\n", scope, len(resourceTypes))
+		for _, name := range resourceTypes {
+			e.printf("    - %s\n", name)
+		}
+	}
+
+	e.printf("\nFilters\n")
+	if !configured {
+		e.printf("  (none)\n")
+		return e.err
+	}
+
+	filters, err := c.explainFilters(accountID)
+	if err != nil {
+		e.printf("  %s, a run against this tenant fails\n", err)
+		return e.err
+	}
+
+	e.printFilters(filters)
+
+	return e.err
+}
+
+// explainFilters returns the filters that a run applies to the account by resource type, together with where each
+// filter was defined.
+func (c *Config) explainFilters(accountID string) (map[string][]sourcedFilter, error) {
+	sources := c.filterSources(accountID)
+
+	filters, err := c.RunFilters(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	explained := make(map[string][]sourcedFilter)
+	for resourceType, resourceFilters := range filters {
+		for _, f := range resourceFilters {
+			sourced := sourcedFilter{Filter: f, Source: "unknown"}
+
+			candidates := sources[resourceType]
+			for i := range candidates {
+				if reflect.DeepEqual(candidates[i].Filter, f) {
+					sourced.Source = candidates[i].Source
+					sources[resourceType] = slices.Delete(candidates, i, i+1)
+					break
+				}
+			}
+
+			explained[resourceType] = append(explained[resourceType], sourced)
+		}
+	}
+
+	return explained, nil
+}
+
+// filterSources returns the filters of the account, its presets, the regions and the protect tags by resource type,
+// with deprecated resource types resolved to their replacements.
+func (c *Config) filterSources(accountID string) map[string][]sourcedFilter {
+	sources := make(map[string][]sourcedFilter)
+
+	add := func(source string, filters filter.Filters) {
+		for resourceType, resourceFilters := range filters {
+			if replacement, ok := c.Deprecations[resourceType]; ok {
+				resourceType = replacement
+			}
+
+			for _, f := range resourceFilters {
+				sources[resourceType] = append(sources[resourceType], sourcedFilter{Filter: f, Source: source})
+			}
+		}
+	}
+
+	if account := c.Accounts[accountID]; account != nil {
+		add("account", account.Filters)
+
+		for _, name := range account.Presets {
+			if preset, ok := c.Presets[name]; ok {
+				add(fmt.Sprintf("preset %s", name), preset.Filters)
+			}
+		}
+	}
+
+	if tags, err := azure.ParseProtectTags(c.ProtectTags); err == nil {
+		sources[filter.Global] = append(sources[filter.Global], c.globalFilters(tags)...)
+	}
+
+	return sources
+}
+
+type explainer struct {
+	w   io.Writer
+	err error
+}
+
+func (e *explainer) printf(format string, a ...any) {
+	if e.err != nil {
+		return
+	}
+
+	_, e.err = fmt.Fprintf(e.w, format, a...)
+}
+
+func (e *explainer) printFilters(filters map[string][]sourcedFilter) {
+	if len(filters) == 0 {
+		e.printf("  (none)\n")
+		return
+	}
+
+	resourceTypes := make([]string, 0, len(filters))
+	for resourceType := range filters {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	for _, resourceType := range resourceTypes {
+		name := resourceType
+		if name == filter.Global {
+			name = "__global__ (all resource types)"
+		}
+
+		e.printf("  %s\n", name)
+		for _, f := range filters[resourceType] {
+			e.printf("    - %s (%s)\n", describeFilter(&f.Filter), f.Source)
+		}
+	}
+}
+
+// describeFilter returns a short human-readable representation of a filter
+func describeFilter(f *filter.Filter) string {
+	property := f.Property
+	if property == "" {
+		property = "name"
+	}
+
+	filterType := f.Type
+	if filterType == filter.Empty {
+		filterType = filter.Exact
+	}
+
+	value := fmt.Sprintf("%q", f.Value)
+	if len(f.Values) > 0 {
+		values := make([]string, 0, len(f.Values))
+		for _, v := range f.Values {
+			values = append(values, fmt.Sprintf("%q", v))
+		}
+		value = "[" + strings.Join(values, ", ") + "]"
+	}
+
+	parts := []string{property, string(filterType), value}
+	if f.Invert {
+		parts = append(parts, "inverted")
+	}
+
+	if f.Group != "" && f.Group != "default" {
+		parts = append(parts, "group "+f.Group)
+	}
+
+	return strings.Join(parts, " ")
+}
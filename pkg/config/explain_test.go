@@ -0,0 +1,97 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestExplain(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/example.yaml",
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.Explain(&buf, "efda01a1-e2e4-4024-89f0-eb29793c605b", nil, nil))
+
+	expected := `Tenant: efda01a1-e2e4-4024-89f0-eb29793c605b
+Status: configured
+Regions: global, eastus
+Presets: common
+Subscriptions: (all)
+
+Resource Types
+  tenant (0)
+  management-group (0)
+  subscription (0)
+  resource-group (0)
+
+Filters
+  ResourceGroup
+    - name exact "Default" (preset common)
+  ServicePrincipal
+    - name exact "some-management-account" (preset common)
+  __global__ (all resource types)
+    - Region NotIn ["global", "eastus"] (regions)
+`
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	assert.NoError(t, config.Explain(&buf, "382ee010-63bb-428b-b0f4-3c9081e32ddb", nil, nil))
+	assert.Contains(t, buf.String(), "Status: blocklisted, a run against this tenant is refused\n")
+	assert.Contains(t, buf.String(), "Presets: (none)\n")
+	assert.Contains(t, buf.String(), "Filters\n  (none)\n")
+}
+
+func TestExplainSubscriptions(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/subscriptions-config.yaml",
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.Explain(&buf, "efda01a1-e2e4-4024-89f0-eb29793c605b", nil, nil))
+	assert.Contains(t, buf.String(), "Subscriptions: sandbox-*, 0b7c3ad0-7b1f-4a3e-8c5e-9f1c2a3b4c5d\n")
+	assert.Contains(t, buf.String(), "Subscription Blocklist: production, 5f9f2a6e-1c56-4c43-9d9b-52c3d0f6c9a1\n")
+	assert.Contains(t, buf.String(), "Max Removals Per Subscription: 250\n")
+}
+
+func TestExplainProtectTags(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/subscriptions-config.yaml",
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.Explain(&buf, "efda01a1-e2e4-4024-89f0-eb29793c605b", nil, nil))
+	assert.Contains(t, buf.String(),
+		"Protect Tags: nuke-protect, env=production (including resource groups and subscriptions)\n")
+	assert.Contains(t, buf.String(), "    - tag:nuke-protect exact \"\" inverted (protect-tags)\n")
+	assert.Contains(t, buf.String(), "    - tag:env exact \"production\" (protect-tags)\n")
+}
+
+func TestExplainDeprecatedResourceTypes(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path:         "testdata/deprecated-types-config.yaml",
+		Deprecations: map[string]string{"AzureResourceGroup": "ResourceGroup"},
+	})
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, config.Explain(&buf, "efda01a1-e2e4-4024-89f0-eb29793c605b", nil, nil))
+	assert.Contains(t, buf.String(), `Filters
+  ResourceGroup
+    - name exact "Production" (account)
+    - name exact "Default" (preset common)
+`)
+	assert.NotContains(t, buf.String(), "AzureResourceGroup")
+
+	filters, err := config.RunFilters("efda01a1-e2e4-4024-89f0-eb29793c605b")
+	assert.NoError(t, err)
+	assert.Len(t, filters["ResourceGroup"], 2)
+	assert.Empty(t, filters["AzureResourceGroup"])
+}
@@ -0,0 +1,288 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// SchemaID is the JSON Schema draft the schema of the configuration is written for
+const SchemaID = "http://json-schema.org/draft-07/schema#"
+
+// deprecatedKeys are the configuration keys that are still supported, but should no longer be used
+var deprecatedKeys = []string{
+	"tenants", "tenant-blocklist", "account-blocklist", "account-blacklist", "targets", "cloud-control",
+}
+
+var (
+	collectionType = reflect.TypeOf(types.Collection{})
+	filtersType    = reflect.TypeOf(filter.Filters{})
+	settingsType   = reflect.TypeOf(settings.Settings{})
+)
+
+// Schema returns the JSON Schema of the configuration file. It is generated from the configuration struct and the
+// registered resource types, so editors can autocomplete and validate resource types, filter properties and settings.
+func Schema() map[string]any {
+	s := &schemaGenerator{
+		definitions: make(map[string]any),
+	}
+
+	names := registry.GetNames()
+	names = append(names, slices.Collect(maps.Keys(registry.GetDeprecatedResourceTypeMapping()))...)
+	sort.Strings(names)
+	s.names = names
+
+	s.definitions["resourceType"] = map[string]any{
+		"description": "The name of a resource type, see the resource types in the documentation.",
+		"type":        "string",
+		"enum":        names,
+	}
+
+	root := s.schemaFor(reflect.TypeOf(Config{}))
+	root["$schema"] = SchemaID
+	root["title"] = "azure-nuke configuration"
+	root["definitions"] = s.definitions
+
+	return root
+}
+
+type schemaGenerator struct {
+	names       []string
+	definitions map[string]any
+}
+
+func (s *schemaGenerator) schemaFor(t reflect.Type) map[string]any {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case collectionType:
+		return map[string]any{
+			"type":  "array",
+			"items": ref("resourceType"),
+		}
+	case filtersType:
+		return s.filtersSchema()
+	case settingsType:
+		return s.settingsSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return s.structSchema(t)
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": s.schemaFor(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": s.schemaFor(t.Elem()),
+		}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		// Note: the schema of any other kind accepts any value
+		return map[string]any{}
+	}
+}
+
+// structSchema returns the schema of a struct from its yaml tags, structs that are embedded inline are merged
+func (s *schemaGenerator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if options == "inline" {
+			maps.Copy(properties, s.structSchema(field.Type)["properties"].(map[string]any))
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		property := s.schemaFor(field.Type)
+		if slices.Contains(deprecatedKeys, name) {
+			property["deprecated"] = true
+		}
+
+		properties[name] = property
+	}
+
+	if extension, ok := extensions[t]; ok {
+		maps.Copy(properties, s.structSchema(extension)["properties"].(map[string]any))
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// filtersSchema returns the schema of the filters by resource type, the filters of each resource type only allow the
+// properties of that resource type.
+func (s *schemaGenerator) filtersSchema() map[string]any {
+	if _, ok := s.definitions["filters"]; !ok {
+		properties := map[string]any{
+			filter.Global: map[string]any{
+				"description": "Filters that apply to all resource types.",
+				"type":        "array",
+				"items":       s.filterSchema(nil),
+			},
+		}
+
+		deprecated := registry.GetDeprecatedResourceTypeMapping()
+		for _, name := range s.names {
+			resourceType := name
+			if replacement, ok := deprecated[name]; ok {
+				resourceType = replacement
+			}
+
+			var typeProperties map[string]string
+			if reg := registry.GetRegistration(resourceType); reg != nil && reg.Resource != nil {
+				typeProperties = resourceProperties(reg.Resource)
+			}
+
+			property := map[string]any{
+				"type":  "array",
+				"items": s.filterSchema(typeProperties),
+			}
+			if resourceType != name {
+				property["deprecated"] = true
+				property["description"] = fmt.Sprintf("Deprecated, use %s instead.", resourceType)
+			}
+
+			properties[name] = property
+		}
+
+		s.definitions["filters"] = map[string]any{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+	}
+
+	return ref("filters")
+}
+
+// filterSchema returns the schema of a single filter, which is either the name of the resource or a filter definition.
+// If properties is nil any property is allowed.
+func (s *schemaGenerator) filterSchema(properties map[string]string) map[string]any {
+	supportedTypes := make([]string, 0, len(filterTypes))
+	for _, t := range filterTypes {
+		if t != filter.Empty {
+			supportedTypes = append(supportedTypes, string(t))
+		}
+	}
+
+	property := map[string]any{
+		"description": "The property of the resource the filter is applied to.",
+		"type":        "string",
+	}
+
+	if properties != nil {
+		names := make([]string, 0, len(properties))
+		prefixes := make([]string, 0)
+		for key := range properties {
+			if prefix, _, ok := strings.Cut(key, "<key>"); ok {
+				prefixes = append(prefixes, prefix)
+				continue
+			}
+
+			names = append(names, key)
+		}
+		sort.Strings(names)
+		sort.Strings(prefixes)
+
+		// Note: tags are added as one property per tag, they are matched by the prefix of the property
+		anyOf := []any{map[string]any{"enum": names}}
+		for _, prefix := range prefixes {
+			anyOf = append(anyOf, map[string]any{"pattern": "^" + regexp.QuoteMeta(prefix) + ".+"})
+		}
+
+		property["anyOf"] = anyOf
+	}
+
+	return map[string]any{
+		"oneOf": []any{
+			map[string]any{
+				"description": "The name of the resource, it has to match exactly.",
+				"type":        "string",
+			},
+			map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"group":    map[string]any{"type": "string"},
+					"type":     map[string]any{"type": "string", "enum": supportedTypes},
+					"property": property,
+					"value":    map[string]any{"type": "string"},
+					"values":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					"invert":   map[string]any{"type": []string{"boolean", "string"}},
+				},
+				"additionalProperties": false,
+			},
+		},
+	}
+}
+
+// settingsSchema returns the schema of the settings, only the resource types that support settings are allowed
+func (s *schemaGenerator) settingsSchema() map[string]any {
+	properties := make(map[string]any)
+
+	for _, name := range s.names {
+		reg := registry.GetRegistration(name)
+		if reg == nil || len(reg.Settings) == 0 {
+			continue
+		}
+
+		supported := make(map[string]any)
+		for _, setting := range reg.Settings {
+			supported[setting] = map[string]any{}
+		}
+
+		properties[name] = map[string]any{
+			"type":                 "object",
+			"properties":           supported,
+			"additionalProperties": false,
+		}
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/definitions/" + name}
+}
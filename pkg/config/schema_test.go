@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// schemaPath returns the value at the path of keys in the schema, after a roundtrip through json
+func schemaPath(t *testing.T, schema any, keys ...string) any {
+	t.Helper()
+
+	raw, err := json.Marshal(schema)
+	assert.NoError(t, err)
+
+	var value any
+	assert.NoError(t, json.Unmarshal(raw, &value))
+
+	for _, key := range keys {
+		m, ok := value.(map[string]any)
+		if !assert.True(t, ok, "expected an object at %s", key) {
+			return nil
+		}
+
+		value = m[key]
+	}
+
+	return value
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+
+	assert.Equal(t, SchemaID, schema["$schema"])
+	assert.Equal(t, false, schemaPath(t, schema, "additionalProperties"))
+
+	for _, key := range []string{"blocklist", "regions", "accounts", "resource-types", "presets", "settings"} {
+		assert.NotNil(t, schemaPath(t, schema, "properties", key), key)
+	}
+
+	assert.Equal(t, true, schemaPath(t, schema, "properties", "tenants", "deprecated"))
+	assert.Equal(t, true, schemaPath(t, schema, "properties", "tenant-blocklist", "deprecated"))
+	assert.Equal(t, true, schemaPath(t, schema, "properties", "resource-types", "properties", "targets", "deprecated"))
+
+	assert.Equal(t, "#/definitions/resourceType",
+		schemaPath(t, schema, "properties", "resource-types", "properties", "includes", "items", "$ref"))
+	assert.Subset(t, schemaPath(t, schema, "definitions", "resourceType", "enum"),
+		[]any{"TestValidateType", "TestValidateOldType"})
+
+	assert.Equal(t, "#/definitions/filters",
+		schemaPath(t, schema, "properties", "accounts", "additionalProperties", "properties", "filters", "$ref"))
+	assert.Equal(t, "string", schemaPath(t, schema,
+		"properties", "accounts", "additionalProperties", "properties", "subscriptions", "items", "type"))
+	assert.Equal(t, "string",
+		schemaPath(t, schema, "properties", "subscription-blocklist", "items", "type"))
+	assert.Equal(t, "#/definitions/filters",
+		schemaPath(t, schema, "properties", "presets", "additionalProperties", "properties", "filters", "$ref"))
+
+	filters := schemaPath(t, schema, "definitions", "filters", "properties", "TestValidateType", "items", "oneOf")
+	property := schemaPath(t, filters.([]any)[1], "properties", "property", "anyOf")
+	assert.Equal(t, []any{
+		map[string]any{"enum": []any{"Name", "Region"}},
+		map[string]any{"pattern": "^tag:.+"},
+	}, property)
+
+	assert.Equal(t, true, schemaPath(t, schema, "definitions", "filters", "properties", "TestValidateOldType", "deprecated"))
+	assert.NotNil(t, schemaPath(t, schema, "definitions", "filters", "properties", "__global__"))
+
+	assert.Equal(t, map[string]any{"PurgeOnDelete": map[string]any{}},
+		schemaPath(t, schema, "properties", "settings", "properties", "TestValidateType", "properties"))
+}
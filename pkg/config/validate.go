@@ -0,0 +1,396 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/docs"
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+// Severity is the severity of a problem found in the configuration
+type Severity string
+
+const (
+	// SeverityError is a problem that prevents the configuration from working as intended
+	SeverityError Severity = "error"
+
+	// SeverityWarning is a problem that does not prevent the configuration from working, but should be fixed
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a single problem found in the configuration
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Message  string   `json:"message"`
+}
+
+func (i *Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Path, i.Message)
+}
+
+// Issues is the list of problems found in the configuration
+type Issues []*Issue
+
+// Errors returns the number of issues with the error severity
+func (i Issues) Errors() int {
+	count := 0
+	for _, issue := range i {
+		if issue.Severity == SeverityError {
+			count++
+		}
+	}
+
+	return count
+}
+
+// filterTypes are the types of filters that are supported by libnuke
+var filterTypes = []filter.Type{
+	filter.Empty, filter.Exact, filter.Glob, filter.Regex, filter.Contains, filter.DateOlderThan,
+	filter.DateOlderThanNow, filter.Suffix, filter.Prefix, filter.NotIn, filter.In,
+}
+
+// Validate checks the configuration file without running anything. It checks the file against the schema of the
+// configuration, the resource types against the registered resource types, the properties used by filters against the
+// properties of each resource type and reports the use of deprecated keys. An error is only returned if the file
+// cannot be read or parsed at all.
+func Validate(path string) (Issues, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &validator{
+		issues:      make(Issues, 0),
+		registered:  registry.GetNames(),
+		deprecated:  registry.GetDeprecatedResourceTypeMapping(),
+		propertyMap: make(map[string]map[string]string),
+	}
+
+	c := &Config{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+
+		// Note: a type error does not abort the decoding, the remainder of the file is still validated
+		for _, e := range typeErr.Errors {
+			if !isExtensionKey(e) {
+				v.add(SeverityError, "", "%s", e)
+			}
+		}
+	}
+
+	v.validate(c)
+
+	sort.SliceStable(v.issues, func(a, b int) bool {
+		return v.issues[a].Path < v.issues[b].Path
+	})
+
+	return v.issues, nil
+}
+
+type validator struct {
+	issues      Issues
+	registered  []string
+	deprecated  map[string]string
+	propertyMap map[string]map[string]string
+}
+
+func (v *validator) add(severity Severity, path, format string, a ...any) {
+	v.issues = append(v.issues, &Issue{
+		Severity: severity,
+		Path:     path,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+func (v *validator) validate(c *Config) {
+	if len(c.Tenants) > 0 {
+		if len(c.Accounts) > 0 {
+			v.add(SeverityError, "tenants", "cannot use both `accounts` and `tenants` configuration keys")
+		} else {
+			v.add(SeverityWarning, "tenants", "deprecated, use `accounts` instead")
+		}
+
+		c.Accounts = c.Tenants
+	}
+
+	if len(c.TenantBlocklist) > 0 {
+		if len(c.Blocklist) > 0 {
+			v.add(SeverityError, "tenant-blocklist", "cannot use both `blocklist` and `tenant-blocklist` configuration keys")
+		} else {
+			v.add(SeverityWarning, "tenant-blocklist", "deprecated, use `blocklist` instead")
+		}
+	}
+
+	for key, ids := range map[string][]string{
+		"account-blocklist": c.AccountBlocklist,
+		"account-blacklist": c.AccountBlacklist,
+	} {
+		if len(ids) > 0 {
+			v.add(SeverityWarning, key, "deprecated, use `blocklist` instead")
+		}
+	}
+
+	if len(c.Blocklist)+len(c.TenantBlocklist)+len(c.AccountBlocklist)+len(c.AccountBlacklist) == 0 {
+		v.add(SeverityError, "blocklist", "no blocklist defined, at least one tenant has to be blocklisted")
+	}
+
+	if _, err := azure.ParseProtectTags(c.ProtectTags); err != nil {
+		v.add(SeverityError, "protect-tags", "%s", err)
+	}
+
+	if c.MaxRemovalsPerSubscription < 0 {
+		v.add(SeverityError, "max-removals-per-subscription", "cannot be negative")
+	}
+
+	v.validateResourceTypes("resource-types", &c.ResourceTypes)
+
+	for name, preset := range c.Presets {
+		v.validateFilters(fmt.Sprintf("presets.%s.filters", name), preset.Filters)
+	}
+
+	for id, account := range c.Accounts {
+		if account == nil {
+			continue
+		}
+
+		path := fmt.Sprintf("accounts.%s", id)
+
+		if slices.Contains(c.Blocklist, id) {
+			v.add(SeverityError, path, "the tenant is configured and blocklisted at the same time")
+		}
+
+		for _, preset := range account.Presets {
+			if _, ok := c.Presets[preset]; !ok {
+				v.add(SeverityError, path+".presets", "unknown preset %q", preset)
+			}
+		}
+
+		v.validateResourceTypes(path+".resource-types", &account.ResourceTypes)
+		v.validateFilters(path+".filters", account.Filters)
+	}
+
+	if c.Settings != nil {
+		for resourceType, setting := range *c.Settings {
+			path := fmt.Sprintf("settings.%s", resourceType)
+			if !v.validateResourceType(path, resourceType) || setting == nil {
+				continue
+			}
+
+			supported := registry.GetRegistration(v.resolve(resourceType)).Settings
+			for key := range *setting {
+				switch {
+				case len(supported) == 0:
+					v.add(SeverityError, path, "the resource type does not support settings")
+				case !slices.Contains(supported, key):
+					v.add(SeverityError, path, "unknown setting %q (supported: %s)", key, list(supported))
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) validateResourceTypes(path string, resourceTypes *config.ResourceTypes) {
+	if len(resourceTypes.Targets) > 0 {
+		v.add(SeverityWarning, path+".targets", "deprecated, use `includes` instead")
+	}
+
+	if len(resourceTypes.CloudControl) > 0 {
+		v.add(SeverityWarning, path+".cloud-control", "deprecated, use `alternatives` instead")
+	}
+
+	for key, names := range map[string][]string{
+		"includes": resourceTypes.Includes,
+		"excludes": resourceTypes.Excludes,
+		"targets":  resourceTypes.Targets,
+	} {
+		for _, name := range names {
+			v.validateResourceType(fmt.Sprintf("%s.%s", path, key), name)
+		}
+	}
+}
+
+// validateResourceType reports resource types that are unknown or deprecated, it returns true if the resource type
+// can be resolved to a registered resource type.
+func (v *validator) validateResourceType(path, name string) bool {
+	if replacement, ok := v.deprecated[name]; ok {
+		v.add(SeverityWarning, path, "resource type %q is deprecated, use %q instead", name, replacement)
+		return true
+	}
+
+	for _, expanded := range registry.ExpandNames([]string{name}) {
+		if !slices.Contains(v.registered, expanded) {
+			v.add(SeverityError, path, "unknown resource type %q", name)
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *validator) validateFilters(path string, filters filter.Filters) {
+	for resourceType, resourceFilters := range filters {
+		filtersPath := fmt.Sprintf("%s.%s", path, resourceType)
+
+		if resourceType == filter.Global {
+			v.validateFilterDefinitions(filtersPath, "", resourceFilters)
+			continue
+		}
+
+		if !v.validateResourceType(filtersPath, resourceType) {
+			continue
+		}
+
+		if replacement, ok := v.deprecated[resourceType]; ok {
+			if _, exists := filters[replacement]; exists {
+				v.add(SeverityError, filtersPath,
+					"using deprecated resource type and replacement: '%s','%s'", resourceType, replacement)
+			}
+		}
+
+		v.validateFilterDefinitions(filtersPath, v.resolve(resourceType), resourceFilters)
+	}
+}
+
+func (v *validator) validateFilterDefinitions(path, resourceType string, filters []filter.Filter) {
+	for i, f := range filters {
+		filterPath := fmt.Sprintf("%s[%d]", path, i)
+
+		if err := f.Validate(); err != nil {
+			v.add(SeverityError, filterPath, "%s", err)
+		}
+
+		if !slices.Contains(filterTypes, f.Type) {
+			v.add(SeverityError, filterPath, "unknown filter type %q", f.Type)
+		}
+
+		if resourceType == "" || f.Property == "" {
+			continue
+		}
+
+		properties := v.properties(resourceType)
+		if len(properties) > 0 && !hasProperty(properties, f.Property) {
+			v.add(SeverityError, filterPath, "unknown property %q for %s (known: %s)",
+				f.Property, resourceType, list(slices.Collect(maps.Keys(properties))))
+		}
+	}
+}
+
+// isExtensionKey returns true if the error of the strict decoding is about a key that this tool adds to a struct of
+// libnuke, these keys are loaded separately.
+func isExtensionKey(decodeErr string) bool {
+	for t, extension := range extensions {
+		for i := 0; i < extension.NumField(); i++ {
+			key, _, _ := strings.Cut(extension.Field(i).Tag.Get("yaml"), ",")
+			if strings.HasSuffix(decodeErr, fmt.Sprintf("field %s not found in type %s", key, t)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// resolve returns the replacement of a deprecated resource type, or the resource type itself
+func (v *validator) resolve(resourceType string) string {
+	if replacement, ok := v.deprecated[resourceType]; ok {
+		return replacement
+	}
+
+	return resourceType
+}
+
+// properties returns the properties of the resource type, as they are documented
+func (v *validator) properties(resourceType string) map[string]string {
+	if properties, ok := v.propertyMap[resourceType]; ok {
+		return properties
+	}
+
+	properties := make(map[string]string)
+	if reg := registry.GetRegistration(resourceType); reg != nil && reg.Resource != nil {
+		properties = resourceProperties(reg.Resource)
+	}
+
+	v.propertyMap[resourceType] = properties
+
+	return properties
+}
+
+// resourceProperties returns the properties of a resource, including the properties of the structs that are embedded
+// inline, such as the BaseResource.
+func resourceProperties(resource any) map[string]string {
+	properties := docs.GeneratePropertiesMap(resource)
+
+	t := reflect.TypeOf(resource)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return properties
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.Anonymous || !slices.Contains(strings.Split(field.Tag.Get("property"), ","), "inline") {
+			continue
+		}
+
+		delete(properties, field.Name)
+
+		embedded := field.Type
+		if embedded.Kind() == reflect.Ptr {
+			embedded = embedded.Elem()
+		}
+
+		maps.Copy(properties, resourceProperties(reflect.New(embedded).Interface()))
+	}
+
+	return properties
+}
+
+// hasProperty returns true if the property is one of the properties, tags are matched against the tag prefix
+func hasProperty(properties map[string]string, property string) bool {
+	if _, ok := properties[property]; ok {
+		return true
+	}
+
+	for key := range properties {
+		if prefix, _, ok := strings.Cut(key, "<key>"); ok && strings.HasPrefix(property, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func list(values []string) string {
+	sorted := slices.Clone(values)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ", ")
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+type testValidateBase struct {
+	Region *string
+}
+
+type testValidateResource struct {
+	*testValidateBase `property:",inline"`
+
+	Name *string
+	Tags map[string]*string
+}
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:              "TestValidateType",
+		Scope:             registry.Scope("test-validate"),
+		Resource:          &testValidateResource{},
+		Lister:            testLister{},
+		Settings:          []string{"PurgeOnDelete"},
+		DeprecatedAliases: []string{"TestValidateOldType"},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	issues, err := Validate("testdata/validate-config.yaml")
+	assert.NoError(t, err)
+
+	account := "accounts.efda01a1-e2e4-4024-89f0-eb29793c605b"
+
+	expected := []string{
+		"error: line 13: field unknown-key not found in type config.ResourceTypes",
+		"error: " + account + ".filters.TestValidateType[2]: unknown filter type \"exac\"",
+		"error: " + account + ".filters.TestValidateType[2]: unknown property \"Nme\" for TestValidateType " +
+			"(known: Name, Region, tag:<key>:)",
+		"warning: " + account + ".filters.TestValidateOldType: " +
+			"resource type \"TestValidateOldType\" is deprecated, use \"TestValidateType\" instead",
+		"error: " + account + ".filters.TestValidateOldType: " +
+			"using deprecated resource type and replacement: 'TestValidateOldType','TestValidateType'",
+		"error: " + account + ".presets: unknown preset \"missing\"",
+		"error: max-removals-per-subscription: cannot be negative",
+		"error: protect-tags: invalid protect tag \"=production\", the key cannot be empty",
+		"error: resource-types.includes: unknown resource type \"TestUnknownType\"",
+		"error: settings.TestValidateType: unknown setting \"Unknown\" (supported: PurgeOnDelete)",
+		"warning: tenant-blocklist: deprecated, use `blocklist` instead",
+	}
+
+	actual := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		actual = append(actual, issue.String())
+	}
+
+	assert.ElementsMatch(t, expected, actual)
+	assert.Equal(t, 9, issues.Errors())
+}
+
+func TestValidateExample(t *testing.T) {
+	issues, err := Validate("testdata/example.yaml")
+	assert.NoError(t, err)
+
+	// Note: the resource types of the example are not registered in this package
+	for _, issue := range issues {
+		assert.Contains(t, issue.Message, "unknown resource type")
+	}
+
+	_, err = Validate("testdata/missing.yaml")
+	assert.Error(t, err)
+}
+
+func TestHasProperty(t *testing.T) {
+	properties := map[string]string{"Name": "", "tag:<key>:": "", "tag:vault:<key>:": ""}
+
+	assert.True(t, hasProperty(properties, "Name"))
+	assert.True(t, hasProperty(properties, "tag:env"))
+	assert.True(t, hasProperty(properties, "tag:vault:env"))
+	assert.False(t, hasProperty(properties, "name"))
+}
@@ -0,0 +1,324 @@
+// Package diff compares two snapshots of a tenant, either inventories or machine-readable run reports, and reports the
+// resources that were added, removed or changed between them.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ekristen/azure-nuke/pkg/report"
+)
+
+// Format is the output format of a diff.
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates and returns the Format for the given string.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unsupported diff format: %s (supported: text, json)", s)
+}
+
+// Kind is the kind of difference of a resource.
+type Kind string
+
+const (
+	KindAdded   Kind = "added"
+	KindRemoved Kind = "removed"
+	KindChanged Kind = "changed"
+)
+
+// ParseKind validates and returns the Kind for the given string.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(s)); k {
+	case KindAdded, KindRemoved, KindChanged:
+		return k, nil
+	}
+
+	return "", fmt.Errorf("unsupported kind of difference: %s (supported: added, removed, changed)", s)
+}
+
+// Snapshot is the set of resources that existed at the time an inventory or a run report was created.
+type Snapshot struct {
+	Items map[string]*report.Item
+}
+
+// Load reads an inventory, a json run report or an ndjson run report. Resources that were removed by the run the
+// report was written for are not part of the snapshot, as they no longer existed when the run finished.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := loadItems(data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s, it must be a json inventory or a json or ndjson run report: %w",
+			path, err)
+	}
+
+	return NewSnapshot(items), nil
+}
+
+// loadItems returns the items of a json document with the items, or of an ndjson run report where every line is
+// either an item or the summary.
+func loadItems(data []byte) ([]*report.Item, error) {
+	var items []*report.Item
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for lines := 0; ; lines++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); errors.Is(err, io.EOF) {
+			if lines == 0 {
+				return nil, fmt.Errorf("the file is empty")
+			}
+
+			return items, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		var line struct {
+			Kind  string         `json:"kind"`
+			Items []*report.Item `json:"items"`
+		}
+		if err := json.Unmarshal(raw, &line); err != nil {
+			return nil, err
+		}
+
+		switch line.Kind {
+		case "item":
+			item := &report.Item{}
+			if err := json.Unmarshal(raw, item); err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		case "summary":
+		case "":
+			if line.Items == nil {
+				return nil, fmt.Errorf("the document has no items")
+			}
+
+			items = append(items, line.Items...)
+		default:
+			return nil, fmt.Errorf("unsupported kind of line: %s", line.Kind)
+		}
+	}
+}
+
+// NewSnapshot creates a snapshot from the items of an inventory or a run report.
+func NewSnapshot(items []*report.Item) *Snapshot {
+	s := &Snapshot{
+		Items: make(map[string]*report.Item),
+	}
+
+	for _, i := range items {
+		if i.State == "finished" {
+			continue
+		}
+
+		s.Items[i.Key()] = i
+	}
+
+	return s
+}
+
+// PropertyChange is the change of a single property of a resource.
+type PropertyChange struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Change is a resource that was added, removed or changed.
+type Change struct {
+	Kind           Kind              `json:"kind"`
+	Type           string            `json:"type"`
+	Name           string            `json:"name,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	SubscriptionID string            `json:"subscription_id,omitempty"`
+	ResourceGroup  string            `json:"resource_group,omitempty"`
+	Properties     []*PropertyChange `json:"properties,omitempty"`
+}
+
+// Result is the difference between two snapshots.
+type Result struct {
+	Added   int       `json:"added"`
+	Removed int       `json:"removed"`
+	Changed int       `json:"changed"`
+	Changes []*Change `json:"changes"`
+}
+
+// Compare returns the resources that were added, removed or changed from the old to the new snapshot. Properties
+// with a key in ignored are not compared.
+func Compare(oldSnapshot, newSnapshot *Snapshot, ignored []string) *Result {
+	result := &Result{
+		Changes: make([]*Change, 0),
+	}
+
+	for key, item := range newSnapshot.Items {
+		previous, ok := oldSnapshot.Items[key]
+		if !ok {
+			result.Added++
+			result.Changes = append(result.Changes, newChange(KindAdded, item))
+			continue
+		}
+
+		properties := compareProperties(previous.Properties, item.Properties, ignored)
+		if len(properties) == 0 {
+			continue
+		}
+
+		change := newChange(KindChanged, item)
+		change.Properties = properties
+
+		result.Changed++
+		result.Changes = append(result.Changes, change)
+	}
+
+	for key, item := range oldSnapshot.Items {
+		if _, ok := newSnapshot.Items[key]; !ok {
+			result.Removed++
+			result.Changes = append(result.Changes, newChange(KindRemoved, item))
+		}
+	}
+
+	sort.SliceStable(result.Changes, func(a, b int) bool {
+		x, y := result.Changes[a], result.Changes[b]
+		if x.Kind != y.Kind {
+			return x.Kind < y.Kind
+		}
+		if x.Type != y.Type {
+			return x.Type < y.Type
+		}
+		if x.ID != y.ID {
+			return x.ID < y.ID
+		}
+		return x.Name < y.Name
+	})
+
+	return result
+}
+
+// Count returns the number of changes of the given kinds
+func (r *Result) Count(kinds ...Kind) int {
+	count := 0
+	for _, c := range r.Changes {
+		if slices.Contains(kinds, c.Kind) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Write writes the result in the given format.
+func (r *Result) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatText:
+		return r.writeText(w)
+	}
+
+	return fmt.Errorf("unsupported diff format: %s", format)
+}
+
+func (r *Result) writeText(w io.Writer) error {
+	symbols := map[Kind]string{
+		KindAdded:   "+",
+		KindRemoved: "-",
+		KindChanged: "~",
+	}
+
+	for _, c := range r.Changes {
+		if _, err := fmt.Fprintf(w, "%s %s - %s\n", symbols[c.Kind], c.Type, c.describe()); err != nil {
+			return err
+		}
+
+		for _, p := range c.Properties {
+			if _, err := fmt.Fprintf(w, "    %s: %q => %q\n", p.Key, p.Old, p.New); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%d added, %d removed, %d changed\n", r.Added, r.Removed, r.Changed)
+	return err
+}
+
+// describe returns the ID of the resource, or its name and location if it has no ID
+func (c *Change) describe() string {
+	if c.ID != "" {
+		return c.ID
+	}
+
+	parts := make([]string, 0, 3)
+	for _, p := range []string{c.SubscriptionID, c.ResourceGroup, c.Name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+
+	return strings.Join(parts, "/")
+}
+
+func newChange(kind Kind, item *report.Item) *Change {
+	return &Change{
+		Kind:           kind,
+		Type:           item.Type,
+		Name:           item.Name,
+		ID:             item.ID,
+		SubscriptionID: item.SubscriptionID,
+		ResourceGroup:  item.ResourceGroup,
+	}
+}
+
+// compareProperties returns the properties that differ between the old and the new properties, a property that is
+// missing on one side is compared as an empty value.
+func compareProperties(oldProperties, newProperties map[string]string, ignored []string) []*PropertyChange {
+	keys := make([]string, 0, len(newProperties))
+	for k := range oldProperties {
+		keys = append(keys, k)
+	}
+	for k := range newProperties {
+		if _, ok := oldProperties[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	changes := make([]*PropertyChange, 0)
+	for _, k := range keys {
+		if slices.Contains(ignored, k) || oldProperties[k] == newProperties[k] {
+			continue
+		}
+
+		changes = append(changes, &PropertyChange{
+			Key: k,
+			Old: oldProperties[k],
+			New: newProperties[k],
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/azure-nuke/pkg/report"
+)
+
+const vnetID = "/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.Network/virtualNetworks/vnet"
+
+func TestCompare(t *testing.T) {
+	oldSnapshot := NewSnapshot([]*report.Item{
+		{Type: "VirtualNetwork", ID: vnetID, Name: "vnet", Properties: map[string]string{
+			"Name": "vnet", "tag:env": "dev", "LastModifiedAt": "2026-10-16T00:00:00Z",
+		}},
+		{Type: "StorageAccount", ID: "/subscriptions/sub-1/storage/old", Name: "old", State: "filtered"},
+		{Type: "StorageAccount", ID: "/subscriptions/sub-1/storage/removed", Name: "removed", State: "finished"},
+		{Type: "ServicePrincipal", Owner: "tenant", Name: "app"},
+	})
+
+	newSnapshot := NewSnapshot([]*report.Item{
+		{Type: "VirtualNetwork", ID: vnetID, Name: "vnet", Properties: map[string]string{
+			"Name": "vnet", "tag:env": "prod", "tag:owner": "alice", "LastModifiedAt": "2026-10-17T00:00:00Z",
+		}},
+		{Type: "StorageAccount", ID: "/subscriptions/sub-1/storage/removed", Name: "removed"},
+		{Type: "ServicePrincipal", Owner: "tenant", Name: "app"},
+	})
+
+	result := Compare(oldSnapshot, newSnapshot, []string{"LastModifiedAt"})
+
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.Removed)
+	assert.Equal(t, 1, result.Changed)
+	assert.Len(t, result.Changes, 3)
+
+	assert.Equal(t, KindAdded, result.Changes[0].Kind)
+	assert.Equal(t, "removed", result.Changes[0].Name)
+
+	assert.Equal(t, KindChanged, result.Changes[1].Kind)
+	assert.Equal(t, vnetID, result.Changes[1].ID)
+	assert.Equal(t, []*PropertyChange{
+		{Key: "tag:env", Old: "dev", New: "prod"},
+		{Key: "tag:owner", Old: "", New: "alice"},
+	}, result.Changes[1].Properties)
+
+	assert.Equal(t, KindRemoved, result.Changes[2].Kind)
+	assert.Equal(t, "old", result.Changes[2].Name)
+
+	assert.Equal(t, 2, result.Count(KindAdded, KindRemoved))
+	assert.Equal(t, 0, Compare(newSnapshot, newSnapshot, nil).Count(KindAdded, KindRemoved, KindChanged))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{
+  "items": [
+    {"type": "VirtualNetwork", "owner": "sub-1/rg-1", "id": "`+vnetID+`", "state": "finished"},
+    {"type": "ResourceGroup", "owner": "sub-1", "name": "rg-1", "state": "filtered"}
+  ],
+  "summary": {"tenant_id": "tenant-1", "total": 2}
+}`), 0600))
+
+	snapshot, err := Load(path)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Items, 1)
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	ndjson := filepath.Join(t.TempDir(), "report.ndjson")
+	assert.NoError(t, os.WriteFile(ndjson, []byte(
+		`{"kind":"item","type":"VirtualNetwork","owner":"sub-1/rg-1","id":"`+vnetID+`","state":"finished"}`+"\n"+
+			`{"kind":"item","type":"ResourceGroup","owner":"sub-1","name":"rg-1","state":"filtered"}`+"\n"+
+			`{"kind":"summary","tenant_id":"tenant-1","total":2}`+"\n"), 0600))
+
+	snapshot, err = Load(ndjson)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Items, 1)
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte(`{"tenant_id":"tenant-1"}`), 0600))
+	_, err = Load(invalid)
+	assert.ErrorContains(t, err, "the document has no items")
+}
+
+func TestWriteText(t *testing.T) {
+	result := Compare(
+		NewSnapshot([]*report.Item{{Type: "ResourceGroup", SubscriptionID: "sub-1", Name: "rg-1"}}),
+		NewSnapshot([]*report.Item{{Type: "VirtualNetwork", ID: vnetID}}),
+		nil,
+	)
+
+	var buf bytes.Buffer
+	assert.NoError(t, result.Write(&buf, FormatText))
+	assert.Equal(t, "+ VirtualNetwork - "+vnetID+"\n- ResourceGroup - sub-1/rg-1\n1 added, 1 removed, 0 changed\n",
+		buf.String())
+}
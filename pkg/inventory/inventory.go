@@ -0,0 +1,200 @@
+// Package inventory provides a listing of the resources of a tenant that is not tied to a nuke run, it can be written
+// as CSV, JSON or as a table grouped by subscription, resource group and resource type.
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/azure-nuke/pkg/report"
+)
+
+// Format is the output format of an inventory.
+type Format string
+
+const (
+	FormatTable Format = "table"
+	FormatCSV   Format = "csv"
+	FormatJSON  Format = "json"
+)
+
+// ParseFormat validates and returns the Format for the given string.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatTable, FormatCSV, FormatJSON:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unsupported inventory format: %s (supported: table, csv, json)", s)
+}
+
+// csvHeader are the columns of the CSV output
+var csvHeader = []string{"subscription_id", "resource_group", "type", "name", "region", "id", "owner"}
+
+// Inventory is the collection of resources that were found in a tenant
+type Inventory struct {
+	TenantID    string         `json:"tenant_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Total       int            `json:"total"`
+	Types       map[string]int `json:"types"`
+	Items       []*report.Item `json:"items"`
+}
+
+// New creates a new empty inventory for the given tenant.
+func New(tenantID string) *Inventory {
+	return &Inventory{
+		TenantID:    tenantID,
+		GeneratedAt: time.Now().UTC(),
+		Types:       make(map[string]int),
+		Items:       make([]*report.Item, 0),
+	}
+}
+
+// Add adds the resource of a scanned queue item to the inventory.
+func (i *Inventory) Add(item *queue.Item) {
+	entry := report.NewItem(item)
+
+	// Note: the state is always new as nothing is processed, it carries no information in an inventory
+	entry.State = ""
+
+	i.Items = append(i.Items, entry)
+	i.Types[entry.Type]++
+	i.Total++
+}
+
+// Sort orders the items by subscription, resource group, type and name.
+func (i *Inventory) Sort() {
+	sort.SliceStable(i.Items, func(a, b int) bool {
+		x, y := i.Items[a], i.Items[b]
+		if x.SubscriptionID != y.SubscriptionID {
+			return x.SubscriptionID < y.SubscriptionID
+		}
+		if x.ResourceGroup != y.ResourceGroup {
+			return x.ResourceGroup < y.ResourceGroup
+		}
+		if x.Type != y.Type {
+			return x.Type < y.Type
+		}
+		return x.Name < y.Name
+	})
+}
+
+// Write writes the inventory in the given format.
+func (i *Inventory) Write(w io.Writer, format Format) error {
+	i.Sort()
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(i)
+	case FormatCSV:
+		return i.writeCSV(w)
+	case FormatTable:
+		return i.writeTable(w)
+	}
+
+	return fmt.Errorf("unsupported inventory format: %s", format)
+}
+
+func (i *Inventory) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, item := range i.Items {
+		if err := cw.Write([]string{
+			item.SubscriptionID, item.ResourceGroup, item.Type, item.Name, item.Region, item.ID, item.Owner,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeTable writes the items grouped by subscription, resource group and type. Resources that do not belong to a
+// subscription are grouped under the tenant, resources that do not belong to a resource group directly under their
+// subscription.
+func (i *Inventory) writeTable(w io.Writer) error {
+	var subscription, resourceGroup, resourceType string
+	first := true
+
+	for _, item := range i.Items {
+		if first || item.SubscriptionID != subscription {
+			subscription, resourceGroup, resourceType = item.SubscriptionID, "", ""
+
+			heading := "Tenant " + i.TenantID
+			if subscription != "" {
+				heading = "Subscription " + subscription
+			}
+
+			if _, err := fmt.Fprintf(w, "%s\n", heading); err != nil {
+				return err
+			}
+		}
+
+		if (first || item.ResourceGroup != resourceGroup) && item.ResourceGroup != "" {
+			resourceGroup, resourceType = item.ResourceGroup, ""
+
+			if _, err := fmt.Fprintf(w, "  Resource Group %s\n", resourceGroup); err != nil {
+				return err
+			}
+		}
+
+		if first || item.Type != resourceType {
+			resourceType = item.Type
+
+			if _, err := fmt.Fprintf(w, "%s%s (%d)\n", i.indent(item, 1), resourceType,
+				i.count(subscription, resourceGroup, resourceType)); err != nil {
+				return err
+			}
+		}
+
+		first = false
+
+		line := fmt.Sprintf("%s- %s", i.indent(item, 2), item.Name)
+		if item.Region != "" {
+			line += fmt.Sprintf(" [%s]", item.Region)
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n%d resources\n", i.Total)
+	return err
+}
+
+// indent returns the indentation of a line at the given depth below the resource group, or below the subscription for
+// resources without resource group.
+func (i *Inventory) indent(item *report.Item, depth int) string {
+	if item.ResourceGroup != "" {
+		depth++
+	}
+
+	return strings.Repeat("  ", depth)
+}
+
+// count returns the number of items of the type in the subscription and resource group
+func (i *Inventory) count(subscriptionID, resourceGroup, resourceType string) int {
+	count := 0
+	for _, item := range i.Items {
+		if item.SubscriptionID == subscriptionID && item.ResourceGroup == resourceGroup && item.Type == resourceType {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,150 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	ID             string
+	Name           string
+	Region         string
+	SubscriptionID string
+	ResourceGroup  string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *testResource) String() string {
+	return r.Name
+}
+
+func (r *testResource) GetRegion() string {
+	return r.Region
+}
+
+func (r *testResource) GetSubscriptionID() string {
+	return r.SubscriptionID
+}
+
+func (r *testResource) GetResourceGroup() string {
+	return r.ResourceGroup
+}
+
+func testInventory() *Inventory {
+	inv := New("tenant-1")
+
+	for _, i := range []*queue.Item{
+		{
+			Type:  "VirtualNetwork",
+			Owner: "sub-1/rg-b",
+			Resource: &testResource{
+				ID:             "/subscriptions/sub-1/resourceGroups/rg-b/providers/Microsoft.Network/virtualNetworks/vnet",
+				Name:           "vnet",
+				Region:         "eastus",
+				SubscriptionID: "sub-1",
+				ResourceGroup:  "rg-b",
+			},
+		},
+		{
+			Type:     "StorageAccount",
+			Owner:    "sub-1/rg-a",
+			Resource: &testResource{Name: "two", Region: "westus", SubscriptionID: "sub-1", ResourceGroup: "rg-a"},
+		},
+		{
+			Type:     "StorageAccount",
+			Owner:    "sub-1/rg-a",
+			Resource: &testResource{Name: "one", Region: "westus", SubscriptionID: "sub-1", ResourceGroup: "rg-a"},
+		},
+		{
+			Type:     "ResourceGroup",
+			Owner:    "sub-1",
+			Resource: &testResource{Name: "rg-a", Region: "westus", SubscriptionID: "sub-1"},
+		},
+		{
+			Type:     "ServicePrincipal",
+			Owner:    "tenant",
+			Resource: &testResource{Name: "app", Region: "global"},
+		},
+	} {
+		inv.Add(i)
+	}
+
+	return inv
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []string{"table", "CSV", "json"} {
+		_, err := ParseFormat(f)
+		assert.NoError(t, err)
+	}
+
+	_, err := ParseFormat("yaml")
+	assert.Error(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testInventory().Write(&buf, FormatCSV))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 6)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{"", "", "ServicePrincipal", "app", "global", "", "tenant"}, records[1])
+	assert.Equal(t, []string{"sub-1", "", "ResourceGroup", "rg-a", "westus", "", "sub-1"}, records[2])
+	assert.Equal(t, "one", records[3][3])
+	assert.Equal(t, "two", records[4][3])
+	assert.Equal(t, "/subscriptions/sub-1/resourceGroups/rg-b/providers/Microsoft.Network/virtualNetworks/vnet",
+		records[5][5])
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testInventory().Write(&buf, FormatJSON))
+
+	var decoded Inventory
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "tenant-1", decoded.TenantID)
+	assert.Equal(t, 5, decoded.Total)
+	assert.Equal(t, 2, decoded.Types["StorageAccount"])
+	assert.Len(t, decoded.Items, 5)
+	assert.Equal(t, "app", decoded.Items[0].Name)
+}
+
+func TestWriteTable(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testInventory().Write(&buf, FormatTable))
+
+	expected := `Tenant tenant-1
+  ServicePrincipal (1)
+    - app [global]
+Subscription sub-1
+  ResourceGroup (1)
+    - rg-a [westus]
+  Resource Group rg-a
+    StorageAccount (2)
+      - one [westus]
+      - two [westus]
+  Resource Group rg-b
+    VirtualNetwork (1)
+      - vnet [eastus]
+
+5 resources
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,283 @@
+// Package metrics provides the Prometheus metrics of a nuke run, they are either served while the run is in progress
+// or pushed to a Pushgateway once the run is finished, for short-lived scheduled runs.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+const namespace = "azure_nuke"
+
+// Metrics are the metrics of a single run. A nil *Metrics is valid and records nothing, so that the run does not
+// have to check whether metrics are enabled.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	discovered *prometheus.GaugeVec
+	filtered   *prometheus.GaugeVec
+	removed    *prometheus.GaugeVec
+	failed     *prometheus.GaugeVec
+
+	apiRequests  *prometheus.CounterVec
+	apiThrottled *prometheus.CounterVec
+
+	scanDuration    prometheus.Gauge
+	removalDuration prometheus.Gauge
+	lastRun         prometheus.Gauge
+	lastRunSuccess  prometheus.Gauge
+
+	mu             sync.Mutex
+	scanStarted    time.Time
+	scanFinished   bool
+	removalStarted time.Time
+}
+
+// New creates the metrics of a run with their own registry
+func New() *Metrics {
+	resourceLabels := []string{"resource_type", "scope"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		discovered: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "resources_discovered",
+			Help:      "The number of resources that were discovered by the scan.",
+		}, resourceLabels),
+		filtered: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "resources_filtered",
+			Help:      "The number of discovered resources that were filtered.",
+		}, resourceLabels),
+		removed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "resources_removed",
+			Help:      "The number of resources that were removed.",
+		}, resourceLabels),
+		failed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "resources_failed",
+			Help:      "The number of resources that failed to be removed.",
+		}, resourceLabels),
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_requests_total",
+			Help:      "The number of responses received from the Azure APIs, including retried requests.",
+		}, []string{"host", "code"}),
+		apiThrottled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "api_throttled_total",
+			Help:      "The number of requests to the Azure APIs that were throttled.",
+		}, []string{"host"}),
+		scanDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "scan_duration_seconds",
+			Help:      "The time it took to scan and filter the resources.",
+		}),
+		removalDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "removal_duration_seconds",
+			Help:      "The time it took to remove the resources, without the prompt.",
+		}),
+		lastRun: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_timestamp_seconds",
+			Help:      "The time the run finished as a unix timestamp.",
+		}),
+		lastRunSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "last_run_success",
+			Help:      "Whether the run finished without an error (1) or not (0).",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.discovered, m.filtered, m.removed, m.failed,
+		m.apiRequests, m.apiThrottled,
+		m.scanDuration, m.removalDuration, m.lastRun, m.lastRunSuccess,
+	)
+
+	return m
+}
+
+// Registry returns the registry the metrics are registered with
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveRequest counts a response of the Azure APIs, it implements the azure.RequestObserver of the throttle. The
+// requests that failed without a response are counted with the code "error".
+func (m *Metrics) ObserveRequest(host string, statusCode int, throttled bool) {
+	if m == nil {
+		return
+	}
+
+	code := strconv.Itoa(statusCode)
+	if statusCode == 0 {
+		code = "error"
+	}
+
+	m.apiRequests.WithLabelValues(host, code).Inc()
+
+	if throttled {
+		m.apiThrottled.WithLabelValues(host).Inc()
+	}
+}
+
+// StartScan marks the start of the scan
+func (m *Metrics) StartScan() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.scanStarted = time.Now()
+}
+
+// FinishScan records the duration of the scan and the discovered and filtered resources of the queue, it is only
+// recorded once per run.
+func (m *Metrics) FinishScan(q *queue.Queue) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.scanFinished || m.scanStarted.IsZero() {
+		return
+	}
+	m.scanFinished = true
+
+	m.scanDuration.Set(time.Since(m.scanStarted).Seconds())
+
+	m.discovered.Reset()
+	m.filtered.Reset()
+
+	for _, item := range q.GetItems() {
+		labels := itemLabels(item)
+
+		m.discovered.With(labels).Inc()
+		if item.GetState() == queue.ItemStateFiltered {
+			m.filtered.With(labels).Inc()
+		}
+	}
+}
+
+// StartRemoval marks the start of the removal, once the removal was confirmed
+func (m *Metrics) StartRemoval() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.removalStarted = time.Now()
+}
+
+// Finish records the outcome of the run. The scan is recorded if it was not yet, which is the case for dry runs, and
+// the removed and failed resources of the queue are recorded if the removal was started.
+func (m *Metrics) Finish(q *queue.Queue, runErr error) {
+	if m == nil {
+		return
+	}
+
+	if q != nil {
+		m.FinishScan(q)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.removalStarted.IsZero() && q != nil {
+		m.removalDuration.Set(time.Since(m.removalStarted).Seconds())
+
+		m.removed.Reset()
+		m.failed.Reset()
+
+		for _, item := range q.GetItems() {
+			switch item.GetState() {
+			case queue.ItemStateFinished:
+				m.removed.With(itemLabels(item)).Inc()
+			case queue.ItemStateFailed:
+				m.failed.With(itemLabels(item)).Inc()
+			}
+		}
+	}
+
+	m.lastRun.SetToCurrentTime()
+
+	m.lastRunSuccess.Set(0)
+	if runErr == nil {
+		m.lastRunSuccess.Set(1)
+	}
+}
+
+// Serve serves the metrics on /metrics of the address until the context is done. The listener is opened before it
+// returns, so that an address that is in use fails the run right away.
+func (m *Metrics) Serve(ctx context.Context, addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.WithField("component", "metrics").WithError(err).Error("metrics server failed")
+		}
+	}()
+
+	return listener.Addr().String(), nil
+}
+
+// Push pushes the metrics to the Pushgateway at the url, replacing the metrics of previous runs of the job for the
+// tenant
+func (m *Metrics) Push(ctx context.Context, url, job, tenantID string) error {
+	return push.New(url, job).
+		Grouping("tenant_id", tenantID).
+		Gatherer(m.registry).
+		PushContext(ctx)
+}
+
+// itemLabels returns the labels of a queue item, the scope is the scope the resource type is registered for
+func itemLabels(item *queue.Item) prometheus.Labels {
+	var scope string
+	if reg := registry.GetRegistration(item.Type); reg != nil {
+		scope = string(reg.Scope)
+	}
+
+	return prometheus.Labels{
+		"resource_type": item.Type,
+		"scope":         scope,
+	}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+const testResourceType = "MetricsTestResource"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:  testResourceType,
+		Scope: "resource-group",
+	})
+}
+
+func testQueue(states ...queue.ItemState) *queue.Queue {
+	q := queue.New()
+	for _, state := range states {
+		q.Items = append(q.Items, &queue.Item{Type: testResourceType, State: state})
+	}
+
+	return q
+}
+
+func TestMetricsRun(t *testing.T) {
+	m := New()
+
+	m.StartScan()
+	m.FinishScan(testQueue(queue.ItemStateNew, queue.ItemStateNew, queue.ItemStateFiltered))
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.discovered.WithLabelValues(testResourceType, "resource-group")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.filtered.WithLabelValues(testResourceType, "resource-group")))
+
+	// Note: the scan is only recorded once, the queue is changed by the removal
+	m.StartRemoval()
+	m.Finish(testQueue(queue.ItemStateFinished, queue.ItemStateFailed, queue.ItemStateFiltered), nil)
+
+	assert.Equal(t, float64(3), testutil.ToFloat64(m.discovered.WithLabelValues(testResourceType, "resource-group")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.removed.WithLabelValues(testResourceType, "resource-group")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.failed.WithLabelValues(testResourceType, "resource-group")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.lastRunSuccess))
+}
+
+func TestMetricsDryRun(t *testing.T) {
+	m := New()
+
+	m.StartScan()
+	m.Finish(testQueue(queue.ItemStateNew), errors.New("failed"))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.discovered.WithLabelValues(testResourceType, "resource-group")))
+	assert.Equal(t, 0, testutil.CollectAndCount(m.removed))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.lastRunSuccess))
+}
+
+func TestMetricsNil(t *testing.T) {
+	var m *Metrics
+
+	assert.NotPanics(t, func() {
+		m.ObserveRequest("management.azure.com", http.StatusOK, false)
+		m.StartScan()
+		m.StartRemoval()
+		m.Finish(testQueue(queue.ItemStateFinished), nil)
+	})
+}
+
+func TestMetricsServe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	m := New()
+	m.ObserveRequest("management.azure.com", http.StatusTooManyRequests, true)
+	m.ObserveRequest("management.azure.com", http.StatusOK, false)
+	m.ObserveRequest("management.azure.com", 0, false)
+
+	addr, err := m.Serve(ctx, "127.0.0.1:0")
+	require.NoError(t, err)
+
+	resp, err := http.Get("http://" + addr + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `azure_nuke_api_requests_total{code="429",host="management.azure.com"} 1`)
+	assert.Contains(t, string(body), `azure_nuke_api_throttled_total{host="management.azure.com"} 1`)
+	assert.Contains(t, string(body), `azure_nuke_api_requests_total{code="error",host="management.azure.com"} 1`)
+}
+
+func TestMetricsPush(t *testing.T) {
+	var path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		path = r.URL.Path
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := New()
+	m.Finish(nil, nil)
+
+	require.NoError(t, m.Push(context.TODO(), server.URL, "azure-nuke", "tenant-1"))
+	assert.Equal(t, "/metrics/job/azure-nuke/tenant_id/tenant-1", path)
+	assert.Contains(t, body, "azure_nuke_last_run_success")
+}
@@ -0,0 +1,208 @@
+// Package plan provides the ability to save the resources a dry run would remove and to later reconcile the results
+// of a new scan against that plan, so that only the reviewed resources are removed.
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/report"
+)
+
+// Version is the version of the plan file format
+const Version = 1
+
+// Plan is the collection of resources that a run would remove.
+type Plan struct {
+	Version   int         `json:"version"`
+	TenantID  string      `json:"tenant_id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Resources []*Resource `json:"resources"`
+}
+
+// Resource is a single resource that is part of the plan.
+type Resource struct {
+	Type           string            `json:"type"`
+	Owner          string            `json:"owner"`
+	ID             string            `json:"id,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	SubscriptionID string            `json:"subscription_id,omitempty"`
+	ResourceGroup  string            `json:"resource_group,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+	Hash           string            `json:"hash"`
+}
+
+// key returns the identity of the resource, it must match the key of the report item.
+func (r *Resource) key() string {
+	return (&report.Item{
+		Type:           r.Type,
+		Owner:          r.Owner,
+		ID:             r.ID,
+		Name:           r.Name,
+		SubscriptionID: r.SubscriptionID,
+		ResourceGroup:  r.ResourceGroup,
+	}).Key()
+}
+
+// New creates a plan from all items in the queue that would be removed.
+func New(tenantID string, q *queue.Queue) *Plan {
+	p := &Plan{
+		Version:   Version,
+		TenantID:  tenantID,
+		CreatedAt: time.Now().UTC(),
+		Resources: make([]*Resource, 0),
+	}
+
+	for _, i := range q.GetItems() {
+		if !removable(i) {
+			continue
+		}
+
+		item := report.NewItem(i)
+		p.Resources = append(p.Resources, &Resource{
+			Type:           item.Type,
+			Owner:          item.Owner,
+			ID:             item.ID,
+			Name:           item.Name,
+			SubscriptionID: item.SubscriptionID,
+			ResourceGroup:  item.ResourceGroup,
+			Properties:     item.Properties,
+			Hash:           Hash(item.Properties),
+		})
+	}
+
+	sort.SliceStable(p.Resources, func(a, b int) bool {
+		return p.Resources[a].key() < p.Resources[b].key()
+	})
+
+	return p
+}
+
+// Load reads a plan from the given path.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Plan{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+
+	if p.Version != Version {
+		return nil, fmt.Errorf("unsupported plan version: %d", p.Version)
+	}
+
+	return p, nil
+}
+
+// Save writes the plan to the given path.
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ResourceTypes returns the unique resource types that are part of the plan.
+func (p *Plan) ResourceTypes() types.Collection {
+	var resourceTypes types.Collection
+	for _, r := range p.Resources {
+		resourceTypes = resourceTypes.Union(types.Collection{r.Type})
+	}
+
+	return resourceTypes
+}
+
+// Reconcile compares the items in the queue against the plan. Any item that would be removed but is not part of the
+// plan, or whose properties have changed since the plan was created, is marked as filtered so that it is not removed.
+// It returns the number of items that were rejected.
+func (p *Plan) Reconcile(q *queue.Queue, log *logrus.Entry) int {
+	planned := make(map[string]*Resource, len(p.Resources))
+	for _, r := range p.Resources {
+		planned[r.key()] = r
+	}
+
+	seen := make(map[string]bool, len(p.Resources))
+	rejected := 0
+
+	for _, i := range q.GetItems() {
+		if !removable(i) {
+			continue
+		}
+
+		item := report.NewItem(i)
+		key := item.Key()
+
+		r, ok := planned[key]
+		if ok {
+			seen[key] = true
+		}
+
+		switch {
+		case !ok:
+			i.State = queue.ItemStateFiltered
+			i.Reason = "not in plan"
+		case r.Hash != Hash(item.Properties):
+			i.State = queue.ItemStateFiltered
+			i.Reason = "properties changed since the plan was created"
+		default:
+			continue
+		}
+
+		rejected++
+		log.
+			WithField("type", item.Type).
+			WithField("owner", item.Owner).
+			WithField("name", item.Name).
+			Warnf("refusing to remove resource: %s", i.Reason)
+	}
+
+	for key, r := range planned {
+		if seen[key] {
+			continue
+		}
+
+		log.
+			WithField("type", r.Type).
+			WithField("owner", r.Owner).
+			WithField("name", r.Name).
+			Info("planned resource no longer exists or is no longer removable")
+	}
+
+	return rejected
+}
+
+// Hash returns a stable hash of the properties of a resource.
+func Hash(props map[string]string) string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "%s=%s\n", k, props[k])
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// removable returns true if the item is in a state where it would be removed
+func removable(i *queue.Item) bool {
+	return i.GetState() == queue.ItemStateNew || i.GetState() == queue.ItemStateNewDependency
+}
@@ -0,0 +1,104 @@
+package plan
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	ID   string
+	Name string
+	Tags map[string]string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *testResource) String() string {
+	return r.Name
+}
+
+func newItem(id, name string, tags map[string]string) *queue.Item {
+	return &queue.Item{
+		Type:     "TestResource",
+		Owner:    "eastus",
+		State:    queue.ItemStateNew,
+		Resource: &testResource{ID: id, Name: name, Tags: tags},
+	}
+}
+
+func TestPlanSaveLoad(t *testing.T) {
+	q := queue.New()
+	q.Items = append(q.Items,
+		newItem("/id/one", "one", nil),
+		newItem("/id/two", "two", nil),
+		&queue.Item{
+			Type:     "TestResource",
+			State:    queue.ItemStateFiltered,
+			Resource: &testResource{ID: "/id/three", Name: "three"},
+		},
+	)
+
+	p := New("tenant-1", q)
+	assert.Len(t, p.Resources, 2)
+	assert.Equal(t, types.Collection{"TestResource"}, p.ResourceTypes())
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	assert.NoError(t, p.Save(path))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant-1", loaded.TenantID)
+	assert.Len(t, loaded.Resources, 2)
+	assert.Equal(t, p.Resources[0].Hash, loaded.Resources[0].Hash)
+}
+
+func TestPlanReconcile(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	planned := queue.New()
+	planned.Items = append(planned.Items,
+		newItem("/id/one", "one", map[string]string{"env": "dev"}),
+		newItem("/id/two", "two", nil),
+		newItem("/id/gone", "gone", nil),
+	)
+
+	p := New("tenant-1", planned)
+
+	q := queue.New()
+	q.Items = append(q.Items,
+		newItem("/id/one", "one", map[string]string{"env": "prod"}),
+		newItem("/id/two", "two", nil),
+		newItem("/id/new", "new", nil),
+	)
+
+	rejected := p.Reconcile(q, logger.WithField("test", true))
+	assert.Equal(t, 2, rejected)
+
+	assert.Equal(t, queue.ItemStateFiltered, q.Items[0].GetState())
+	assert.Equal(t, "properties changed since the plan was created", q.Items[0].GetReason())
+	assert.Equal(t, queue.ItemStateNew, q.Items[1].GetState())
+	assert.Equal(t, queue.ItemStateFiltered, q.Items[2].GetState())
+	assert.Equal(t, "not in plan", q.Items[2].GetReason())
+}
+
+func TestHashIsStable(t *testing.T) {
+	a := Hash(map[string]string{"a": "1", "b": "2"})
+	b := Hash(map[string]string{"b": "2", "a": "1"})
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, Hash(map[string]string{"a": "1", "b": "3"}))
+}
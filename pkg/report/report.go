@@ -0,0 +1,221 @@
+// Package report provides a machine-readable representation of the items processed by a nuke run so that the
+// results can be consumed by other tooling (dashboards, diffs between runs, etc.).
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// Format is the output format of a report.
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat validates and returns the Format for the given string.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatText, FormatJSON, FormatNDJSON:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("unsupported output format: %s (supported: text, json, ndjson)", s)
+}
+
+// Item is the report representation of a single queue item.
+type Item struct {
+	Kind           string            `json:"kind,omitempty"`
+	Type           string            `json:"type"`
+	Owner          string            `json:"owner"`
+	Name           string            `json:"name,omitempty"`
+	ID             string            `json:"id,omitempty"`
+	Region         string            `json:"region,omitempty"`
+	SubscriptionID string            `json:"subscription_id,omitempty"`
+	ResourceGroup  string            `json:"resource_group,omitempty"`
+	Properties     map[string]string `json:"properties,omitempty"`
+	State          string            `json:"state"`
+	Reason         string            `json:"reason,omitempty"`
+}
+
+// Summary is the aggregate of all items in the report.
+type Summary struct {
+	Kind       string         `json:"kind,omitempty"`
+	TenantID   string         `json:"tenant_id"`
+	DryRun     bool           `json:"dry_run"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Total      int            `json:"total"`
+	States     map[string]int `json:"states"`
+	Error      string         `json:"error,omitempty"`
+
+	// SkippedSubscriptions are the subscriptions that could not be scanned, with the reason as value
+	SkippedSubscriptions map[string]string `json:"skipped_subscriptions,omitempty"`
+
+	// LiftedLocks are the management locks that were removed because they blocked the removal of a resource
+	LiftedLocks []*LiftedLock `json:"lifted_locks,omitempty"`
+}
+
+// LiftedLock is a management lock that was removed so that a resource could be removed
+type LiftedLock struct {
+	ResourceID string `json:"resource_id"`
+	LockID     string `json:"lock_id"`
+	Name       string `json:"name"`
+	Level      string `json:"level"`
+	Scope      string `json:"scope"`
+
+	// Restored is true if the lock was applied again after the resource was removed
+	Restored bool `json:"restored"`
+}
+
+// Report is a collection of items and the summary of a run.
+type Report struct {
+	Items   []*Item  `json:"items"`
+	Summary *Summary `json:"summary"`
+}
+
+// regionGetter, subscriptionGetter and resourceGroupGetter are implemented by resources embedding the BaseResource
+type regionGetter interface {
+	GetRegion() string
+}
+
+type subscriptionGetter interface {
+	GetSubscriptionID() string
+}
+
+type resourceGroupGetter interface {
+	GetResourceGroup() string
+}
+
+// New creates a new empty report for the given tenant.
+func New(tenantID string, dryRun bool) *Report {
+	return &Report{
+		Items: make([]*Item, 0),
+		Summary: &Summary{
+			TenantID:  tenantID,
+			DryRun:    dryRun,
+			StartedAt: time.Now().UTC(),
+			States:    make(map[string]int),
+		},
+	}
+}
+
+// NewItem converts a queue item into its report representation.
+func NewItem(i *queue.Item) *Item {
+	item := &Item{
+		Type:  i.Type,
+		Owner: i.Owner,
+		State: i.GetState().String(),
+	}
+
+	if i.GetState() == queue.ItemStateFailed || i.GetState() == queue.ItemStateFiltered {
+		item.Reason = i.GetReason()
+	}
+
+	if r, ok := i.Resource.(resource.LegacyStringer); ok {
+		item.Name = r.String()
+	}
+
+	if r, ok := i.Resource.(regionGetter); ok {
+		item.Region = r.GetRegion()
+	}
+
+	if r, ok := i.Resource.(subscriptionGetter); ok {
+		item.SubscriptionID = r.GetSubscriptionID()
+	}
+
+	if r, ok := i.Resource.(resourceGroupGetter); ok {
+		item.ResourceGroup = r.GetResourceGroup()
+	}
+
+	if r, ok := i.Resource.(resource.PropertyGetter); ok {
+		item.Properties = make(map[string]string)
+		for k, v := range r.Properties() {
+			// Note: keys prefixed with an underscore are internal to libnuke
+			if strings.HasPrefix(k, "_") {
+				continue
+			}
+			item.Properties[k] = v
+		}
+
+		item.ID = item.Properties["ID"]
+	}
+
+	return item
+}
+
+// Key returns the identity of the item, it is the resource type plus the ARM or Graph ID when available, otherwise
+// it falls back to the location and name of the resource.
+func (i *Item) Key() string {
+	if i.ID != "" {
+		return fmt.Sprintf("%s|%s", i.Type, strings.ToLower(i.ID))
+	}
+
+	return strings.Join([]string{i.Type, i.Owner, i.SubscriptionID, i.ResourceGroup, i.Name}, "|")
+}
+
+// AddQueue adds all items from the queue to the report.
+func (r *Report) AddQueue(q *queue.Queue) {
+	if q == nil {
+		return
+	}
+
+	for _, i := range q.GetItems() {
+		r.Items = append(r.Items, NewItem(i))
+	}
+}
+
+// Finish computes the summary of the report, err is the error the run ended with, if any.
+func (r *Report) Finish(err error) {
+	r.Summary.FinishedAt = time.Now().UTC()
+	r.Summary.Total = len(r.Items)
+	r.Summary.States = make(map[string]int)
+
+	for _, i := range r.Items {
+		r.Summary.States[i.State]++
+	}
+
+	if err != nil {
+		r.Summary.Error = err.Error()
+	}
+}
+
+// Write writes the report in the requested format. The text format is handled by the logging of libnuke, so nothing
+// is written for it.
+func (r *Report) Write(w io.Writer, format Format) error {
+	sort.SliceStable(r.Items, func(a, b int) bool {
+		return r.Items[a].Key() < r.Items[b].Key()
+	})
+
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, i := range r.Items {
+			line := *i
+			line.Kind = "item"
+			if err := enc.Encode(&line); err != nil {
+				return err
+			}
+		}
+
+		summary := *r.Summary
+		summary.Kind = "summary"
+		return enc.Encode(&summary)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testResource struct {
+	ID             string
+	Name           string
+	Region         string
+	SubscriptionID string
+	ResourceGroup  string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *testResource) String() string {
+	return r.Name
+}
+
+func (r *testResource) GetRegion() string {
+	return r.Region
+}
+
+func (r *testResource) GetSubscriptionID() string {
+	return r.SubscriptionID
+}
+
+func (r *testResource) GetResourceGroup() string {
+	return r.ResourceGroup
+}
+
+func testQueue() *queue.Queue {
+	q := queue.New()
+	q.Items = append(q.Items,
+		&queue.Item{
+			Type:  "TestResource",
+			Owner: "eastus",
+			State: queue.ItemStateFinished,
+			Resource: &testResource{
+				ID:             "/subscriptions/sub-1/resourceGroups/rg-1/providers/Test/resources/one",
+				Name:           "one",
+				Region:         "eastus",
+				SubscriptionID: "sub-1",
+				ResourceGroup:  "rg-1",
+			},
+		},
+		&queue.Item{
+			Type:     "TestResource",
+			Owner:    "eastus",
+			State:    queue.ItemStateFailed,
+			Reason:   "ScopeLocked",
+			Resource: &testResource{Name: "two", Region: "eastus", SubscriptionID: "sub-1", ResourceGroup: "rg-1"},
+		},
+	)
+
+	return q
+}
+
+func TestNewItem(t *testing.T) {
+	q := testQueue()
+
+	item := NewItem(q.Items[0])
+	assert.Equal(t, "TestResource", item.Type)
+	assert.Equal(t, "one", item.Name)
+	assert.Equal(t, "eastus", item.Region)
+	assert.Equal(t, "sub-1", item.SubscriptionID)
+	assert.Equal(t, "rg-1", item.ResourceGroup)
+	assert.Equal(t, "finished", item.State)
+	assert.Equal(t, "/subscriptions/sub-1/resourceGroups/rg-1/providers/Test/resources/one", item.ID)
+	assert.Equal(t, "one", item.Properties["Name"])
+	assert.Empty(t, item.Reason)
+
+	_, ok := item.Properties["_tagPrefix"]
+	assert.False(t, ok)
+
+	failed := NewItem(q.Items[1])
+	assert.Equal(t, "failed", failed.State)
+	assert.Equal(t, "ScopeLocked", failed.Reason)
+}
+
+func TestWriteJSON(t *testing.T) {
+	r := New("tenant-1", true)
+	r.AddQueue(testQueue())
+	r.Finish(fmt.Errorf("failed"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf, FormatJSON))
+
+	decoded := &Report{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Len(t, decoded.Items, 2)
+	assert.Equal(t, "tenant-1", decoded.Summary.TenantID)
+	assert.Equal(t, 2, decoded.Summary.Total)
+	assert.Equal(t, 1, decoded.Summary.States["failed"])
+	assert.Equal(t, 1, decoded.Summary.States["finished"])
+	assert.Equal(t, "failed", decoded.Summary.Error)
+	assert.True(t, decoded.Summary.DryRun)
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	r := New("tenant-1", false)
+	r.AddQueue(testQueue())
+	r.Finish(nil)
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Write(&buf, FormatNDJSON))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+
+	for _, line := range lines[:2] {
+		item := &Item{}
+		assert.NoError(t, json.Unmarshal([]byte(line), item))
+		assert.Equal(t, "item", item.Kind)
+	}
+
+	summary := &Summary{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[2]), summary))
+	assert.Equal(t, "summary", summary.Kind)
+	assert.Equal(t, 2, summary.Total)
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("NDJSON")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatNDJSON, f)
+
+	_, err = ParseFormat("yaml")
+	assert.Error(t, err)
+}
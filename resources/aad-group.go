@@ -37,6 +37,7 @@ func (l AzureAdGroupLister) List(_ context.Context, o interface{}) ([]resource.R
 	client := msgraph.NewGroupsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.MicrosoftGraph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	resources := make([]resource.Resource, 0)
 
@@ -41,6 +41,7 @@ func (l AzureADUserLister) List(ctx context.Context, o interface{}) ([]resource.
 	client := msgraph.NewUsersClient()
 	client.BaseClient.Authorizer = opts.Authorizers.Graph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list azure ad users")
 
@@ -0,0 +1,35 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestAzureADUserListAndRemove(t *testing.T) {
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, "/beta/users", http.StatusOK, "azure-ad-user-list.json")
+	server.Respond(http.MethodDelete, "/beta/users/11111111-1111-1111-1111-111111111111", http.StatusNoContent, nil)
+
+	lister := AzureADUserLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers: server.Authorizers(),
+		TenantID:    azuretest.TenantID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	user := resources[0].(*AzureADUser)
+	assert.Equal(t, "Test User", user.String())
+	assert.Equal(t, "global", user.GetRegion())
+	assert.Equal(t, "test.user@contoso.onmicrosoft.com", user.Properties().Get("UPN"))
+
+	assert.NoError(t, user.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, "/beta/users/11111111-1111-1111-1111-111111111111"), 1)
+}
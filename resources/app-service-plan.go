@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &AppServicePlan{},
 		Lister:   &AppServicePlanLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Web/serverFarms", AppServicePlanResource)
 }
 
 type AppServicePlanLister struct{}
@@ -32,7 +36,7 @@ func (l AppServicePlanLister) List(ctx context.Context, o interface{}) ([]resour
 
 	log := logrus.WithField("r", AppServicePlanResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armappservice.NewPlansClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armappservice.NewPlansClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -50,9 +54,10 @@ func (l AppServicePlanLister) List(ctx context.Context, o interface{}) ([]resour
 
 		for _, g := range page.Value {
 			resources = append(resources, &AppServicePlan{
-				BaseResource: &BaseResource{
-					ResourceGroup: &opts.ResourceGroup,
-				},
+				BaseResource: (&BaseResource{
+					SubscriptionID: &opts.SubscriptionID,
+					ResourceGroup:  &opts.ResourceGroup,
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(g.ID))),
 				client: client,
 				Name:   *g.Name,
 			})
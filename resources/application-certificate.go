@@ -64,6 +64,7 @@ func (l ApplicationCertificateLister) List(ctx context.Context, o interface{}) (
 	client := msgraph.NewApplicationsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.Graph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list application certificates")
 
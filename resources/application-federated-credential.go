@@ -68,6 +68,7 @@ func (l ApplicationFederatedCredentialLister) List(ctx context.Context, o interf
 	client := msgraph.NewApplicationsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.Graph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list application federated creds")
 
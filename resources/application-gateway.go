@@ -25,6 +25,8 @@ func init() {
 		Resource: &ApplicationGateway{},
 		Lister:   &ApplicationGatewayLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/applicationGateways", ApplicationGatewayResource)
 }
 
 type ApplicationGatewayLister struct{}
@@ -35,7 +37,8 @@ func (l ApplicationGatewayLister) List(ctx context.Context, o interface{}) ([]re
 
 	log := logrus.WithField("r", ApplicationGatewayResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armnetwork.NewApplicationGatewaysClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewApplicationGatewaysClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -54,11 +57,11 @@ func (l ApplicationGatewayLister) List(ctx context.Context, o interface{}) ([]re
 
 		for _, entry := range page.Value {
 			resources = append(resources, &ApplicationGateway{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         ptr.String("global"),
 					SubscriptionID: ptr.String(opts.SubscriptionID),
 					ResourceGroup:  ptr.String(opts.ResourceGroup),
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entry.ID))),
 				client: client,
 				ID:     entry.ID,
 				Name:   entry.Name,
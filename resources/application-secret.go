@@ -66,6 +66,7 @@ func (l ApplicationSecretLister) List(ctx context.Context, o interface{}) ([]res
 	client := msgraph.NewApplicationsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.Graph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list application secrets")
 
@@ -38,6 +38,7 @@ func (l ApplicationLister) List(ctx context.Context, o interface{}) ([]resource.
 	client := msgraph.NewApplicationsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.Graph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list applications")
 
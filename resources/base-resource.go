@@ -1,9 +1,14 @@
 package resources
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/gotidy/ptr"
 
 	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
 )
 
 // BaseResource is a base struct that all Azure resources should embed to provide common fields and methods.
@@ -11,6 +16,58 @@ type BaseResource struct {
 	Region         *string `description:"The region that the resource group belongs to."`
 	SubscriptionID *string `description:"The subscription ID that the resource group belongs to."`
 	ResourceGroup  *string `description:"The resource group that the resource belongs to."`
+
+	CreatedAt      *time.Time `description:"When the resource was created, from its systemData or the createdTime tracked by ARM."`
+	CreatedBy      *string    `description:"The identity that created the resource, empty if its API does not return systemData."`
+	CreatedByType  *string    `description:"The type of the creator (User, Application, ManagedIdentity, Key), empty without systemData."`
+	LastModifiedAt *time.Time `description:"When the resource was last modified, from its systemData or the changedTime tracked by ARM."`
+	LastModifiedBy *string    `description:"The identity that last modified the resource, empty if its API does not return systemData."`
+}
+
+// systemData is the common JSON representation of the systemData of ARM resources
+type systemData struct {
+	CreatedAt      *time.Time `json:"createdAt"`
+	CreatedBy      *string    `json:"createdBy"`
+	CreatedByType  *string    `json:"createdByType"`
+	LastModifiedAt *time.Time `json:"lastModifiedAt"`
+	LastModifiedBy *string    `json:"lastModifiedBy"`
+}
+
+// WithSystemData sets the creation and modification fields from the systemData of an ARM resource. Every package of
+// the Azure SDK has its own SystemData type, they all share the same JSON representation, which is used to convert
+// them. A nil systemData leaves the fields empty.
+func (r *BaseResource) WithSystemData(data interface{}) *BaseResource {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return r
+	}
+
+	sd := &systemData{}
+	if err := json.Unmarshal(raw, sd); err != nil {
+		return r
+	}
+
+	r.CreatedAt = sd.CreatedAt
+	r.CreatedBy = sd.CreatedBy
+	r.CreatedByType = sd.CreatedByType
+	r.LastModifiedAt = sd.LastModifiedAt
+	r.LastModifiedBy = sd.LastModifiedBy
+
+	return r
+}
+
+// WithResourceTimes sets the creation and modification times tracked by Azure Resource Manager, it is used for the
+// resource types whose SDK model does not expose the systemData of the resource. The identities that created and
+// modified the resource are not tracked, so they are left empty.
+func (r *BaseResource) WithResourceTimes(times *azure.ResourceTimes) *BaseResource {
+	if times == nil {
+		return r
+	}
+
+	r.CreatedAt = times.CreatedTime
+	r.LastModifiedAt = times.ChangedTime
+
+	return r
 }
 
 // GetRegion returns the region that the resource belongs to.
@@ -30,8 +87,20 @@ func (r *BaseResource) GetResourceGroup() string {
 
 // BeforeEnqueue is a special hook that is called from github.com/ekristen/libnuke that allows the resource to
 // modify the queue item before it is put on the queue, in this case it allows us to modify the owner field to
-// set it as the region so the behavior of this tool is consistent with the other tools based on libnuke and regions
+// set it as the region so the behavior of this tool is consistent with the other tools based on libnuke and regions.
+// It also sets the subscription of the lister if the resource has none, and filters the resources within resource
+// groups and subscriptions that are protected by a tag.
 func (r *BaseResource) BeforeEnqueue(item interface{}) {
 	i := item.(*queue.Item)
 	i.Owner = ptr.ToString(r.Region)
+
+	if opts, ok := i.Opts.(*azure.ListerOpts); ok {
+		// Note: the removal limits and the prompt count the resources by subscription, a resource of a lister that
+		// does not set its subscription would be counted for the tenant instead
+		if r.SubscriptionID == nil && opts.SubscriptionID != "" {
+			r.SubscriptionID = ptr.String(opts.SubscriptionID)
+		}
+
+		opts.TagProtection.Protect(i)
+	}
 }
@@ -44,7 +44,7 @@ func (l BudgetLister) List(pctx context.Context, o interface{}) ([]resource.Reso
 
 	log := logrus.WithField("r", BudgetResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armconsumption.NewBudgetsClient(opts.Authorizers.IdentityCreds, nil)
+	client, err := armconsumption.NewBudgetsClient(opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -23,6 +23,8 @@ func init() {
 		Resource: &ContainerRegistry{},
 		Lister:   &ContainerRegistryLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.ContainerRegistry/registries", ContainerRegistryResource)
 }
 
 type ContainerRegistry struct {
@@ -59,7 +61,8 @@ func (l ContainerRegistryLister) List(ctx context.Context, o interface{}) ([]res
 
 	log := logrus.WithField("r", ContainerRegistryResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armcontainerregistry.NewRegistriesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armcontainerregistry.NewRegistriesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -75,11 +78,11 @@ func (l ContainerRegistryLister) List(ctx context.Context, o interface{}) ([]res
 
 		for _, entity := range page.Value {
 			resources = append(resources, &ContainerRegistry{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithSystemData(entity.SystemData),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -0,0 +1,169 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const CustomRoleDefinitionResource = "CustomRoleDefinition"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     CustomRoleDefinitionResource,
+		Scope:    azure.SubscriptionScope,
+		Resource: &CustomRoleDefinition{},
+		Lister:   &CustomRoleDefinitionLister{},
+		DependsOn: []string{
+			ManagementGroupRoleAssignmentResource,
+			SubscriptionRoleAssignmentResource,
+			ResourceGroupRoleAssignmentResource,
+			ResourceRoleAssignmentResource,
+		},
+	})
+}
+
+type CustomRoleDefinition struct {
+	*BaseResource `property:",inline"`
+
+	client *armauthorization.RoleDefinitionsClient
+
+	ID               *string `property:"-"`
+	Name             *string `description:"The ID of the role definition."`
+	RoleName         *string `description:"The name of the role."`
+	AssignableScopes string  `description:"The scopes the role can be assigned at, separated by commas."`
+	PermissionsCount int     `description:"The number of actions and not actions granted by the permissions of the role."`
+
+	// scope is the first assignable scope of the role, the role is removed at it
+	scope                 *string
+	assignableScopes      []string
+	targetSubscriptionIDs []string
+}
+
+func (r *CustomRoleDefinition) Remove(ctx context.Context) error {
+	_, err := r.client.Delete(ctx, *r.scope, *r.Name, nil)
+	return err
+}
+
+func (r *CustomRoleDefinition) Filter() error {
+	// Note: removing the role would break it for the scopes that are not targeted by the run
+	for _, scope := range r.assignableScopes {
+		if !slices.Contains(r.targetSubscriptionIDs, subscriptionOfScope(scope)) {
+			return fmt.Errorf("assignable at %s, which is not within the targeted subscriptions", scope)
+		}
+	}
+
+	return nil
+}
+
+func (r *CustomRoleDefinition) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *CustomRoleDefinition) String() string {
+	return ptr.ToString(r.RoleName)
+}
+
+type CustomRoleDefinitionLister struct{}
+
+func (l CustomRoleDefinitionLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", CustomRoleDefinitionResource).WithField("s", opts.SubscriptionID)
+
+	resources := make([]resource.Resource, 0)
+
+	client, err := armauthorization.NewRoleDefinitionsClient(opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	scope := fmt.Sprintf("/subscriptions/%s", opts.SubscriptionID)
+
+	targetSubscriptionIDs := make([]string, 0, len(opts.SubscriptionIDs)+1)
+	for _, subscriptionID := range append([]string{opts.SubscriptionID}, opts.SubscriptionIDs...) {
+		targetSubscriptionIDs = append(targetSubscriptionIDs, strings.ToLower(subscriptionID))
+	}
+
+	log.Trace("attempting to list custom role definitions")
+
+	// Note: the roles at and below the subscription include those that are only assignable at resource groups, the
+	// filter cannot be combined with the type, so the built-in roles are skipped below
+	pager := client.NewListPager(scope, &armauthorization.RoleDefinitionsClientListOptions{
+		Filter: ptr.String("atScopeAndBelow()"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, def := range page.Value {
+			if def.Properties == nil || ptr.ToString(def.Properties.RoleType) != "CustomRole" {
+				continue
+			}
+
+			assignableScopes := make([]string, 0, len(def.Properties.AssignableScopes))
+			for _, assignableScope := range def.Properties.AssignableScopes {
+				assignableScopes = append(assignableScopes, ptr.ToString(assignableScope))
+			}
+
+			// Note: a role that is assignable in several subscriptions is listed by each of them, it is only
+			// handled by the scanner of the subscription of its first assignable scope
+			if len(assignableScopes) == 0 ||
+				subscriptionOfScope(assignableScopes[0]) != strings.ToLower(opts.SubscriptionID) {
+				continue
+			}
+
+			var permissionsCount int
+			for _, permission := range def.Properties.Permissions {
+				if permission != nil {
+					permissionsCount += len(permission.Actions) + len(permission.NotActions)
+				}
+			}
+
+			resources = append(resources, &CustomRoleDefinition{
+				BaseResource: &BaseResource{
+					Region:         ptr.String("global"),
+					SubscriptionID: &opts.SubscriptionID,
+				},
+				client:                client,
+				scope:                 ptr.String(assignableScopes[0]),
+				assignableScopes:      assignableScopes,
+				targetSubscriptionIDs: targetSubscriptionIDs,
+				ID:                    def.ID,
+				Name:                  def.Name,
+				RoleName:              def.Properties.RoleName,
+				AssignableScopes:      strings.Join(assignableScopes, ","),
+				PermissionsCount:      permissionsCount,
+			})
+		}
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
+
+// subscriptionOfScope returns the lower case ID of the subscription of a scope, or an empty string if the scope is
+// not within a subscription
+func subscriptionOfScope(scope string) string {
+	parts := strings.Split(strings.Trim(scope, "/"), "/")
+	if len(parts) < 2 || !strings.EqualFold(parts[0], "subscriptions") {
+		return ""
+	}
+
+	return strings.ToLower(parts[1])
+}
@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestCustomRoleDefinitionListFilterAndRemove(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.Authorization/roleDefinitions"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path, http.StatusOK, "custom-role-definition-list.json")
+	server.Respond(http.MethodDelete, path+"/dddddddd-dddd-dddd-dddd-dddddddddddd", http.StatusOK, nil)
+
+	resourceGroupPath := "/subscriptions/" + azuretest.SubscriptionID +
+		"/resourceGroups/rg-test/providers/Microsoft.Authorization/roleDefinitions/abababab-abab-abab-abab-abababababab"
+	server.Respond(http.MethodDelete, resourceGroupPath, http.StatusOK, nil)
+
+	lister := CustomRoleDefinitionLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:     server.Authorizers(),
+		SubscriptionID:  azuretest.SubscriptionID,
+		SubscriptionIDs: []string{azuretest.SubscriptionID, "00000000-0000-0000-0000-000000000003"},
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	// Note: the roles that are only assignable below the subscription are listed as well
+	requests := server.Requests(http.MethodGet, path)
+	require.Len(t, requests, 1)
+	assert.Contains(t, requests[0].Query, "atScopeAndBelow")
+
+	role := resources[0].(*CustomRoleDefinition)
+	assert.Equal(t, "pipeline-test-role", role.String())
+	assert.Equal(t, "3", role.Properties().Get("PermissionsCount"))
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000002,"+
+		"/subscriptions/00000000-0000-0000-0000-000000000002/resourceGroups/rg-test",
+		role.Properties().Get("AssignableScopes"))
+	assert.NoError(t, role.Filter())
+
+	shared := resources[1].(*CustomRoleDefinition)
+	assert.EqualError(t, shared.Filter(), "assignable at /subscriptions/00000000-0000-0000-0000-000000000009, "+
+		"which is not within the targeted subscriptions")
+
+	// Note: a role that is only assignable at a resource group is found and removed at its resource group
+	resourceGroupRole := resources[2].(*CustomRoleDefinition)
+	assert.Equal(t, "resource-group-role", resourceGroupRole.String())
+	assert.NoError(t, resourceGroupRole.Filter())
+
+	assert.NoError(t, role.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, path+"/dddddddd-dddd-dddd-dddd-dddddddddddd"), 1)
+
+	assert.NoError(t, resourceGroupRole.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, resourceGroupPath), 1)
+}
@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &DNSZone{},
 		Lister:   &DNSZoneLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/dnszones", DNSZoneResource)
 }
 
 type DNSZoneLister struct{}
@@ -37,7 +41,7 @@ func (l DNSZoneLister) List(ctx context.Context, o interface{}) ([]resource.Reso
 
 	log.Trace("start")
 
-	client, err := armdns.NewZonesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armdns.NewZonesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -56,11 +60,11 @@ func (l DNSZoneLister) List(ctx context.Context, o interface{}) ([]resource.Reso
 
 		for _, g := range page.Value {
 			resources = append(resources, &DNSZone{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         g.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(g.ID))),
 				client: client,
 				Name:   g.Name,
 				Tags:   g.Tags,
@@ -0,0 +1,205 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const GenericARMResourceResource = "GenericARMResource"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     GenericARMResourceResource,
+		Scope:    azure.ResourceGroupScope,
+		Resource: &GenericARMResource{},
+		Lister:   &GenericARMResourceLister{},
+	})
+
+	// Note: the generic resource would remove everything in a resource group, so it has to be explicitly included
+	azure.RegisterOptIn(GenericARMResourceResource)
+}
+
+type GenericARMResource struct {
+	*BaseResource `property:",inline"`
+
+	client     *armresources.Client
+	apiVersion string
+
+	ID        *string            `description:"The ARM ID of the resource."`
+	Name      *string            `description:"The name of the resource."`
+	Type      *string            `description:"The ARM resource type, for example Microsoft.Web/sites."`
+	Kind      *string            `description:"The kind of the resource, if the resource type has kinds."`
+	Location  *string            `description:"The location of the resource."`
+	ManagedBy *string            `description:"The ID of the resource that manages this resource."`
+	Tags      map[string]*string `description:"The tags assigned to the resource."`
+}
+
+func (r *GenericARMResource) Filter() error {
+	if ptr.ToString(r.ManagedBy) != "" {
+		return fmt.Errorf("managed by %s", ptr.ToString(r.ManagedBy))
+	}
+
+	if r.apiVersion == "" {
+		return fmt.Errorf("unable to determine api version for %s", ptr.ToString(r.Type))
+	}
+
+	return nil
+}
+
+func (r *GenericARMResource) Remove(ctx context.Context) error {
+	poller, err := r.client.BeginDeleteByID(ctx, *r.ID, r.apiVersion, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (r *GenericARMResource) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *GenericARMResource) String() string {
+	return ptr.ToString(r.Name)
+}
+
+type GenericARMResourceLister struct{}
+
+func (l GenericARMResourceLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", GenericARMResourceResource).WithField("s", opts.SubscriptionID)
+
+	client, err := armresources.NewClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	providersClient, err := armresources.NewProvidersClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0)
+	apiVersions := newAPIVersionResolver(providersClient)
+
+	log.Trace("attempting to list generic resources")
+
+	pager := client.NewListByResourceGroupPager(opts.ResourceGroup, &armresources.ClientListByResourceGroupOptions{
+		Expand: ptr.String("createdTime,changedTime"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entity := range page.Value {
+			// Note: typed resources are skipped so that they are not removed twice
+			if azure.IsTypedARMType(ptr.ToString(entity.Type)) {
+				continue
+			}
+
+			apiVersion, err := apiVersions.resolve(ctx, ptr.ToString(entity.Type))
+			if err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, &GenericARMResource{
+				BaseResource: (&BaseResource{
+					Region:         entity.Location,
+					ResourceGroup:  &opts.ResourceGroup,
+					SubscriptionID: &opts.SubscriptionID,
+				}).WithResourceTimes(&azure.ResourceTimes{
+					CreatedTime: entity.CreatedTime,
+					ChangedTime: entity.ChangedTime,
+				}),
+				client:     client,
+				apiVersion: apiVersion,
+				ID:         entity.ID,
+				Name:       entity.Name,
+				Type:       entity.Type,
+				Kind:       entity.Kind,
+				Location:   entity.Location,
+				ManagedBy:  entity.ManagedBy,
+				Tags:       entity.Tags,
+			})
+		}
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
+
+// apiVersionResolver resolves the API version of ARM resource types from the metadata of the resource providers,
+// each resource provider is only requested once.
+type apiVersionResolver struct {
+	client    *armresources.ProvidersClient
+	providers map[string]*armresources.Provider
+}
+
+func newAPIVersionResolver(client *armresources.ProvidersClient) *apiVersionResolver {
+	return &apiVersionResolver{
+		client:    client,
+		providers: make(map[string]*armresources.Provider),
+	}
+}
+
+// resolve returns the default API version of the resource type, or the newest stable API version if the resource
+// provider does not define a default. An empty string is returned if the resource type is unknown.
+func (r *apiVersionResolver) resolve(ctx context.Context, resourceType string) (string, error) {
+	namespace, typeName, found := strings.Cut(resourceType, "/")
+	if !found {
+		return "", nil
+	}
+
+	provider, ok := r.providers[strings.ToLower(namespace)]
+	if !ok {
+		res, err := r.client.Get(ctx, namespace, nil)
+		if err != nil {
+			return "", err
+		}
+
+		provider = &res.Provider
+		r.providers[strings.ToLower(namespace)] = provider
+	}
+
+	for _, rt := range provider.ResourceTypes {
+		if !strings.EqualFold(ptr.ToString(rt.ResourceType), typeName) {
+			continue
+		}
+
+		if ptr.ToString(rt.DefaultAPIVersion) != "" {
+			return *rt.DefaultAPIVersion, nil
+		}
+
+		// Note: the api versions are sorted newest first, preview versions are only used if there is nothing else
+		for _, v := range rt.APIVersions {
+			if !strings.Contains(ptr.ToString(v), "preview") {
+				return *v, nil
+			}
+		}
+
+		if len(rt.APIVersions) > 0 {
+			return ptr.ToString(rt.APIVersions[0]), nil
+		}
+	}
+
+	return "", nil
+}
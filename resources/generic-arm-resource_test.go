@@ -0,0 +1,54 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestGenericARMResourceListFilterAndRemove(t *testing.T) {
+	rgPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-dev"
+	sitePath := rgPath + "/providers/Microsoft.Web/sites/app-dev"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, rgPath+"/resources", http.StatusOK, "generic-arm-resource-list.json")
+	server.RespondWithFixture(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/providers/Microsoft.Web",
+		http.StatusOK, "generic-arm-resource-provider.json")
+	server.Respond(http.MethodDelete, sitePath, http.StatusOK, nil)
+
+	lister := GenericARMResourceLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-dev",
+	})
+	require.NoError(t, err)
+	require.Len(t, resources, 2, "typed resources must be skipped")
+
+	// the resource provider is only requested once for both sites
+	assert.Len(t, server.Requests(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/providers/Microsoft.Web"), 1)
+
+	site := resources[0].(*GenericARMResource)
+	props := site.Properties()
+	assert.Equal(t, "app-dev", site.String())
+	assert.Equal(t, "Microsoft.Web/sites", props.Get("Type"))
+	assert.Equal(t, "app,linux", props.Get("Kind"))
+	assert.Equal(t, "eastus", props.Get("Location"))
+	assert.Equal(t, "dev", props.Get("tag:env"))
+	assert.NoError(t, site.Filter())
+
+	managed := resources[1].(*GenericARMResource)
+	assert.ErrorContains(t, managed.Filter(), "managed by")
+
+	assert.NoError(t, site.Remove(context.TODO()))
+
+	deletes := server.Requests(http.MethodDelete, sitePath)
+	require.Len(t, deletes, 1)
+	assert.Equal(t, "api-version=2023-12-01", deletes[0].Query)
+}
@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &IPAllocation{},
 		Lister:   &IPAllocationLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/IpAllocations", IPAllocationResource)
 }
 
 type IPAllocationLister struct{}
@@ -32,7 +36,7 @@ func (l IPAllocationLister) List(ctx context.Context, o interface{}) ([]resource
 
 	log := logrus.WithField("r", IPAllocationResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armnetwork.NewIPAllocationsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewIPAllocationsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +54,11 @@ func (l IPAllocationLister) List(ctx context.Context, o interface{}) ([]resource
 
 		for _, entity := range page.Value {
 			resources = append(resources, &IPAllocation{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -0,0 +1,126 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const KeyVaultDeletedResource = "KeyVaultDeleted"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     KeyVaultDeletedResource,
+		Scope:    azure.SubscriptionScope,
+		Resource: &KeyVaultDeleted{},
+		Lister:   &KeyVaultDeletedLister{},
+	})
+}
+
+type KeyVaultDeletedLister struct{}
+
+func (l KeyVaultDeletedLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", KeyVaultDeletedResource).WithField("s", opts.SubscriptionID)
+
+	client, err := armkeyvault.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0)
+
+	log.Trace("attempting to list deleted key vaults")
+
+	pager := client.NewListDeletedPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entity := range page.Value {
+			properties := entity.Properties
+			if properties == nil {
+				properties = &armkeyvault.DeletedVaultProperties{}
+			}
+
+			resources = append(resources, &KeyVaultDeleted{
+				BaseResource: &BaseResource{
+					Region:         properties.Location,
+					SubscriptionID: &opts.SubscriptionID,
+				},
+				client:                 client,
+				Name:                   entity.Name,
+				Location:               properties.Location,
+				VaultID:                properties.VaultID,
+				DeletionDate:           properties.DeletionDate,
+				ScheduledPurgeDate:     properties.ScheduledPurgeDate,
+				PurgeProtectionEnabled: properties.PurgeProtectionEnabled,
+				Tags:                   properties.Tags,
+			})
+		}
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
+
+// KeyVaultDeleted is a soft-deleted key vault, the name of the vault stays reserved until it is purged
+type KeyVaultDeleted struct {
+	*BaseResource `property:",inline"`
+
+	client                 *armkeyvault.VaultsClient
+	Name                   *string
+	Location               *string
+	VaultID                *string    `description:"The resource ID of the original vault."`
+	DeletionDate           *time.Time `description:"When the vault was deleted."`
+	ScheduledPurgeDate     *time.Time `description:"When the vault will be purged automatically."`
+	PurgeProtectionEnabled *bool      `description:"Whether purge protection is enabled on the deleted vault."`
+	Tags                   map[string]*string
+}
+
+func (r *KeyVaultDeleted) Filter() error {
+	if !ptr.ToBool(r.PurgeProtectionEnabled) {
+		return nil
+	}
+
+	if r.ScheduledPurgeDate != nil {
+		return fmt.Errorf("purge protection is enabled, scheduled to be purged on %s",
+			r.ScheduledPurgeDate.Format(time.RFC3339))
+	}
+
+	return errors.New("purge protection is enabled")
+}
+
+func (r *KeyVaultDeleted) Remove(ctx context.Context) error {
+	poller, err := r.client.BeginPurgeDeleted(ctx, *r.Name, *r.Location, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+func (r *KeyVaultDeleted) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *KeyVaultDeleted) String() string {
+	return *r.Name
+}
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestKeyVaultDeletedListFilterAndPurge(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.KeyVault"
+	purgePath := path + "/locations/eastus/deletedVaults/kv-dev/purge"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path+"/deletedVaults", http.StatusOK, "key-vault-deleted-list.json")
+	server.Respond(http.MethodPost, purgePath, http.StatusOK, nil)
+
+	lister := KeyVaultDeletedLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	dev := resources[0].(*KeyVaultDeleted)
+	assert.Equal(t, "kv-dev", dev.String())
+	assert.Equal(t, "eastus", dev.Properties().Get("Location"))
+	assert.Equal(t, "2024-05-01T10:00:00Z", dev.Properties().Get("DeletionDate"))
+	assert.Equal(t, "team-a", dev.Properties().Get("tag:owner"))
+	assert.NoError(t, dev.Filter())
+
+	prod := resources[1].(*KeyVaultDeleted)
+	assert.EqualError(t, prod.Filter(), "purge protection is enabled, scheduled to be purged on 2024-07-31T10:00:00Z")
+
+	assert.NoError(t, dev.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodPost, purgePath), 1)
+}
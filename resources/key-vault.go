@@ -3,12 +3,14 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/azure-nuke/pkg/azure"
@@ -22,7 +24,12 @@ func init() {
 		Scope:    azure.SubscriptionScope,
 		Resource: &KeyVault{},
 		Lister:   &KeyVaultLister{},
+		Settings: []string{
+			"PurgeOnDelete",
+		},
 	})
+
+	azure.RegisterARMType("Microsoft.KeyVault/vaults", KeyVaultResource)
 }
 
 type KeyVaultLister struct{}
@@ -32,7 +39,7 @@ func (l KeyVaultLister) List(ctx context.Context, o interface{}) ([]resource.Res
 
 	log := logrus.WithField("r", KeyVaultResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armkeyvault.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armkeyvault.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -49,15 +56,23 @@ func (l KeyVaultLister) List(ctx context.Context, o interface{}) ([]resource.Res
 		}
 
 		for _, entity := range page.Value {
+			properties := entity.Properties
+			if properties == nil {
+				properties = &armkeyvault.VaultProperties{}
+			}
+
 			resources = append(resources, &KeyVault{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  azure.GetResourceGroupFromID(*entity.ID),
 					SubscriptionID: &opts.SubscriptionID,
-				},
-				client: client,
-				Name:   entity.Name,
-				Tags:   entity.Tags,
+				}).WithSystemData(entity.SystemData),
+				client:                client,
+				Name:                  entity.Name,
+				Location:              entity.Location,
+				EnableSoftDelete:      properties.EnableSoftDelete,
+				EnablePurgeProtection: properties.EnablePurgeProtection,
+				Tags:                  entity.Tags,
 			})
 		}
 	}
@@ -70,16 +85,50 @@ func (l KeyVaultLister) List(ctx context.Context, o interface{}) ([]resource.Res
 type KeyVault struct {
 	*BaseResource `property:",inline"`
 
-	client *armkeyvault.VaultsClient
-	Name   *string
-	Tags   map[string]*string
+	client                *armkeyvault.VaultsClient
+	settings              *libsettings.Setting
+	Name                  *string
+	Location              *string
+	EnableSoftDelete      *bool `description:"Whether soft delete is enabled, soft delete is enabled when not set."`
+	EnablePurgeProtection *bool `description:"Whether purge protection is enabled, a protected vault cannot be purged."`
+	Tags                  map[string]*string
 }
 
 func (r *KeyVault) Remove(ctx context.Context) error {
-	_, err := r.client.Delete(ctx, *r.ResourceGroup, *r.Name, nil)
+	if _, err := r.client.Delete(ctx, *r.ResourceGroup, *r.Name, nil); err != nil {
+		return err
+	}
+
+	if r.settings == nil || !r.settings.GetBool("PurgeOnDelete") {
+		return nil
+	}
+
+	// Soft delete is enabled on all vaults unless explicitly disabled, in which case the vault is already gone
+	if !ptr.ToBoolDef(r.EnableSoftDelete, true) {
+		return nil
+	}
+
+	if ptr.ToBool(r.EnablePurgeProtection) {
+		logrus.
+			WithField("r", KeyVaultResource).
+			WithField("name", *r.Name).
+			Warn("purge protection is enabled, the vault remains soft-deleted until its retention period ends")
+		return nil
+	}
+
+	poller, err := r.client.BeginPurgeDeleted(ctx, *r.Name, ptr.ToString(r.Location), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
 	return err
 }
 
+func (r *KeyVault) Settings(setting *libsettings.Setting) {
+	r.settings = setting
+}
+
 func (r *KeyVault) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
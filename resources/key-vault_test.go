@@ -0,0 +1,50 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestKeyVaultRemovePurgeOnDelete(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.KeyVault"
+	devPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-dev/providers/Microsoft.KeyVault/vaults/kv-dev"
+	prodPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-prod/providers/Microsoft.KeyVault/vaults/kv-prod"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path+"/vaults", http.StatusOK, "key-vault-list.json")
+	server.Respond(http.MethodDelete, devPath, http.StatusOK, nil)
+	server.Respond(http.MethodDelete, prodPath, http.StatusOK, nil)
+	server.Respond(http.MethodPost, path+"/locations/eastus/deletedVaults/kv-dev/purge", http.StatusOK, nil)
+
+	lister := KeyVaultLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	setting := &libsettings.Setting{"PurgeOnDelete": true}
+
+	dev := resources[0].(*KeyVault)
+	dev.Settings(setting)
+	assert.NoError(t, dev.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, devPath), 1)
+	assert.Len(t, server.Requests(http.MethodPost, path+"/locations/eastus/deletedVaults/kv-dev/purge"), 1)
+
+	// purge protected vaults are only deleted, the purge request would be rejected
+	prod := resources[1].(*KeyVault)
+	prod.Settings(setting)
+	assert.Equal(t, "true", prod.Properties().Get("EnablePurgeProtection"))
+	assert.NoError(t, prod.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, prodPath), 1)
+}
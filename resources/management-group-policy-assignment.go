@@ -0,0 +1,125 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armpolicy"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const ManagementGroupPolicyAssignmentResource = "ManagementGroupPolicyAssignment"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ManagementGroupPolicyAssignmentResource,
+		Scope:    azure.ManagementGroupScope,
+		Resource: &ManagementGroupPolicyAssignment{},
+		Lister:   &ManagementGroupPolicyAssignmentLister{},
+	})
+}
+
+type ManagementGroupPolicyAssignment struct {
+	*BaseResource `property:",inline"`
+
+	client            *armpolicy.AssignmentsClient
+	Name              string `description:"The name of the policy assignment."`
+	Scope             string `description:"The scope the policy is assigned at."`
+	EnforcementMode   string `description:"The enforcement mode of the policy assignment."`
+	ManagementGroupID string `description:"The ID of the management group the policy is assigned to."`
+}
+
+func (r *ManagementGroupPolicyAssignment) Filter() error {
+	if strings.HasPrefix(r.Name, "sys.") {
+		return fmt.Errorf("cannot remove built-in policy")
+	}
+	return nil
+}
+
+func (r *ManagementGroupPolicyAssignment) Remove(ctx context.Context) error {
+	_, err := r.client.Delete(ctx, r.Scope, r.Name, nil)
+	return err
+}
+
+func (r *ManagementGroupPolicyAssignment) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ManagementGroupPolicyAssignment) String() string {
+	return r.Name
+}
+
+type ManagementGroupPolicyAssignmentLister struct{}
+
+func (l ManagementGroupPolicyAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", ManagementGroupPolicyAssignmentResource).WithField("mg", opts.ManagementGroupID)
+
+	clientOptions := opts.Authorizers.ARMClientOptions()
+	clientOptions.APIVersion = "2024-04-01"
+
+	client, err := armpolicy.NewAssignmentsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0)
+
+	log.Trace("attempting to list policy assignments")
+
+	mgScope := azure.GetManagementGroupScope(opts.ManagementGroupID)
+
+	pager := client.NewListForManagementGroupPager(opts.ManagementGroupID,
+		&armpolicy.AssignmentsClientListForManagementGroupOptions{
+			Filter: ptr.String("atScope()"),
+		})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range page.Value {
+			scope := ""
+			if g.Properties != nil && g.Properties.Scope != nil {
+				scope = *g.Properties.Scope
+			}
+
+			// Note: atScope() also returns the assignments inherited from parent management groups, those are
+			// handled by the scanner of the management group they are assigned to.
+			if !strings.EqualFold(scope, mgScope) {
+				continue
+			}
+
+			enforcementMode := ""
+			if g.Properties != nil && g.Properties.EnforcementMode != nil {
+				enforcementMode = string(*g.Properties.EnforcementMode)
+			}
+
+			resources = append(resources, &ManagementGroupPolicyAssignment{
+				BaseResource: (&BaseResource{
+					Region: ptr.String("global"),
+				}).WithSystemData(g.SystemData),
+				client:            client,
+				Name:              ptr.ToString(g.Name),
+				Scope:             scope,
+				EnforcementMode:   enforcementMode,
+				ManagementGroupID: opts.ManagementGroupID,
+			})
+		}
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armpolicy"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const ManagementGroupPolicyDefinitionResource = "ManagementGroupPolicyDefinition"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ManagementGroupPolicyDefinitionResource,
+		Scope:    azure.ManagementGroupScope,
+		Resource: &ManagementGroupPolicyDefinition{},
+		Lister:   &ManagementGroupPolicyDefinitionLister{},
+		DependsOn: []string{
+			ManagementGroupPolicyAssignmentResource,
+		},
+	})
+}
+
+type ManagementGroupPolicyDefinition struct {
+	*BaseResource `property:",inline"`
+
+	client            *armpolicy.DefinitionsClient
+	Name              *string `description:"The name of the policy definition."`
+	DisplayName       string  `description:"The display name of the policy definition."`
+	PolicyType        string  `property:"name=Type" description:"The type of the policy definition."`
+	ManagementGroupID string  `description:"The ID of the management group the policy is defined at."`
+}
+
+func (r *ManagementGroupPolicyDefinition) Remove(ctx context.Context) error {
+	_, err := r.client.DeleteAtManagementGroup(ctx, r.ManagementGroupID, *r.Name, nil)
+	return err
+}
+
+func (r *ManagementGroupPolicyDefinition) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ManagementGroupPolicyDefinition) String() string {
+	return *r.Name
+}
+
+type ManagementGroupPolicyDefinitionLister struct{}
+
+func (l ManagementGroupPolicyDefinitionLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", ManagementGroupPolicyDefinitionResource).WithField("mg", opts.ManagementGroupID)
+
+	clientOptions := opts.Authorizers.ARMClientOptions()
+	clientOptions.APIVersion = "2023-04-01"
+
+	client, err := armpolicy.NewDefinitionsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, clientOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0)
+
+	log.Trace("attempting to list policy definitions")
+
+	mgScope := azure.GetManagementGroupScope(opts.ManagementGroupID)
+
+	pager := client.NewListByManagementGroupPager(opts.ManagementGroupID, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range page.Value {
+			policyType := ""
+			if g.Properties != nil && g.Properties.PolicyType != nil {
+				policyType = string(*g.Properties.PolicyType)
+			}
+
+			// Filtering out BuiltIn Policy Definitions, same as the subscription level policy definitions
+			if policyType == "BuiltIn" || policyType == "Static" {
+				continue
+			}
+
+			// Note: definitions from parent management groups are also returned, those are handled by the scanner of
+			// the management group they are defined at.
+			if !strings.HasPrefix(strings.ToLower(ptr.ToString(g.ID)), strings.ToLower(mgScope)+"/") {
+				continue
+			}
+
+			displayName := ""
+			if g.Properties != nil && g.Properties.DisplayName != nil {
+				displayName = *g.Properties.DisplayName
+			}
+
+			resources = append(resources, &ManagementGroupPolicyDefinition{
+				BaseResource: (&BaseResource{
+					Region: ptr.String("global"),
+				}).WithSystemData(g.SystemData),
+				client:            client,
+				Name:              g.Name,
+				DisplayName:       displayName,
+				PolicyType:        policyType,
+				ManagementGroupID: opts.ManagementGroupID,
+			})
+		}
+	}
+
+	log.WithField("total", len(resources)).Trace("done")
+
+	return resources, nil
+}
@@ -0,0 +1,129 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const ManagementGroupRoleAssignmentResource = "ManagementGroupRoleAssignment"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ManagementGroupRoleAssignmentResource,
+		Scope:    azure.ManagementGroupScope,
+		Resource: &ManagementGroupRoleAssignment{},
+		Lister:   &ManagementGroupRoleAssignmentLister{},
+	})
+}
+
+type ManagementGroupRoleAssignment struct {
+	*BaseResource `property:",inline"`
+
+	client *armauthorization.RoleAssignmentsClient
+
+	ID                *string `property:"-"`
+	Name              *string `description:"The name of the role assignment."`
+	RoleName          *string `description:"The name of the role that is assigned."`
+	RoleDefinitionID  *string `description:"The ID of the role definition that is assigned."`
+	PrincipalID       *string `description:"The ID of the principal the role is assigned to."`
+	PrincipalName     *string `description:"The display name of the principal, unknown if it does not exist."`
+	PrincipalType     *string `description:"The type of the principal (user, group, service_principal, foreign_group, device or unknown)."`
+	Orphaned          bool    `description:"Whether the user, group or service principal no longer exists in the directory."`
+	ManagementGroupID string  `description:"The ID of the management group the role is assigned at."`
+	scope             *string
+}
+
+func (r *ManagementGroupRoleAssignment) Remove(ctx context.Context) error {
+	_, err := r.client.Delete(ctx, *r.scope, *r.Name, nil)
+	return err
+}
+
+func (r *ManagementGroupRoleAssignment) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ManagementGroupRoleAssignment) String() string {
+	return fmt.Sprintf("%s -> %s", ptr.ToString(r.PrincipalName), ptr.ToString(r.RoleName))
+}
+
+type ManagementGroupRoleAssignmentLister struct{}
+
+func (l ManagementGroupRoleAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", ManagementGroupRoleAssignmentResource).WithField("mg", opts.ManagementGroupID)
+
+	client, err := armauthorization.NewRoleAssignmentsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0)
+
+	mgScope := azure.GetManagementGroupScope(opts.ManagementGroupID)
+
+	log.Trace("attempting to list role assignments")
+
+	pager := client.NewListForScopePager(mgScope, &armauthorization.RoleAssignmentsClientListForScopeOptions{
+		Filter: ptr.String("atScope()"),
+	})
+
+	var assignments []*armauthorization.RoleAssignment
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range page.Value {
+			// Note: atScope() also returns the assignments inherited from parent management groups, those are
+			// handled by the scanner of the management group they are assigned at.
+			if !strings.EqualFold(ptr.ToString(t.Properties.Scope), mgScope) {
+				continue
+			}
+
+			assignments = append(assignments, t)
+		}
+	}
+
+	resolved, err := resolveRoleAssignments(ctx, opts.GetDirectory(), assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resolved {
+		resources = append(resources, &ManagementGroupRoleAssignment{
+			BaseResource: &BaseResource{
+				Region: ptr.String("global"),
+			},
+			client:            client,
+			scope:             t.Properties.Scope,
+			ID:                t.ID,
+			Name:              t.Name,
+			RoleName:          t.RoleName,
+			RoleDefinitionID:  t.RoleDefinitionID,
+			PrincipalID:       t.Properties.PrincipalID,
+			PrincipalName:     ptr.String(t.Principal.Name),
+			PrincipalType:     t.PrincipalType,
+			Orphaned:          t.Orphaned,
+			ManagementGroupID: opts.ManagementGroupID,
+		})
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestManagementGroupRoleAssignmentList(t *testing.T) {
+	scope := azure.GetManagementGroupScope("mg-dev")
+	roleDefinitionID := "/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7"
+
+	assignment := func(name, scope, principalID, principalType string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":   scope + "/providers/Microsoft.Authorization/roleAssignments/" + name,
+			"name": name,
+			"properties": map[string]string{
+				"roleDefinitionId": roleDefinitionID,
+				"principalId":      principalID,
+				"principalType":    principalType,
+				"scope":            scope,
+			},
+		}
+	}
+
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, scope+"/providers/Microsoft.Authorization/roleAssignments", http.StatusOK,
+		map[string]interface{}{
+			"value": []interface{}{
+				assignment("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa", scope, "11111111-1111-1111-1111-111111111111", "User"),
+				assignment("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb", scope, "33333333-3333-3333-3333-333333333333", "Group"),
+				// Note: inherited from the parent management group
+				assignment("cccccccc-cccc-cccc-cccc-cccccccccccc", azure.GetManagementGroupScope("mg-root"),
+					"11111111-1111-1111-1111-111111111111", "User"),
+			},
+		})
+	server.Respond(http.MethodGet, roleDefinitionID, http.StatusOK, map[string]interface{}{
+		"properties": map[string]string{"roleName": "Reader"},
+	})
+	server.Respond(http.MethodPost, "/v1.0/directoryObjects/getByIds", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{{
+			"@odata.type": "#microsoft.graph.user",
+			"id":          "11111111-1111-1111-1111-111111111111",
+			"displayName": "Test User",
+		}},
+	})
+
+	lister := ManagementGroupRoleAssignmentLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:       server.Authorizers(),
+		ManagementGroupID: "mg-dev",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	user := resources[0].(*ManagementGroupRoleAssignment)
+	assert.Equal(t, "Test User -> Reader", user.String())
+	assert.Equal(t, "user", user.Properties().Get("PrincipalType"))
+	assert.Equal(t, "acdd72a7-3385-48ef-bd42-f606fba81ae7", user.Properties().Get("RoleDefinitionID"))
+	assert.False(t, user.Orphaned)
+
+	deleted := resources[1].(*ManagementGroupRoleAssignment)
+	assert.Equal(t, "unknown -> Reader", deleted.String())
+	assert.Equal(t, "group", deleted.Properties().Get("PrincipalType"))
+	assert.Equal(t, "true", deleted.Properties().Get("Orphaned"))
+}
@@ -30,17 +30,19 @@ func init() {
 type ManagementLock struct {
 	*BaseResource `property:",inline"`
 
-	client    *armlocks.ManagementLocksClient
-	ID        *string `property:"-"`
-	Name      *string
-	LockLevel string
+	client     *armlocks.ManagementLocksClient
+	ID         *string `property:"-"`
+	Name       *string
+	LockLevel  string
+	Scope      string `description:"The scope the lock is applied to, either the resource group or a resource in it."`
+	ScopeLevel string `description:"The level of the scope the lock is applied to (ResourceGroup or Resource)."`
 }
 
 func (r *ManagementLock) Remove(ctx context.Context) error {
 	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(30*time.Second))
 	defer cancel()
 
-	_, err := r.client.DeleteAtResourceGroupLevel(ctx, *r.ResourceGroup, *r.Name, nil)
+	_, err := r.client.DeleteByScope(ctx, r.Scope, *r.Name, nil)
 	return err
 }
 
@@ -64,7 +66,7 @@ func (l ManagementLockLister) List(ctx context.Context, o interface{}) ([]resour
 
 	resources := make([]resource.Resource, 0)
 
-	client, err := armlocks.NewManagementLocksClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armlocks.NewManagementLocksClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
@@ -79,21 +81,31 @@ func (l ManagementLockLister) List(ctx context.Context, o interface{}) ([]resour
 		}
 
 		for _, lock := range page.Value {
+			// Note: locks of the subscription apply to the resource group as well, they are listed by the
+			// SubscriptionManagementLock resource
+			scope := azure.LockScope(ptr.ToString(lock.ID))
+			scopeLevel := azure.LockScopeLevel(scope)
+			if scopeLevel == "Subscription" {
+				continue
+			}
+
 			var lockLevel string
 			if lock.Properties != nil && lock.Properties.Level != nil {
 				lockLevel = string(*lock.Properties.Level)
 			}
 
 			resources = append(resources, &ManagementLock{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         ptr.String("global"),
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
-				client:    client,
-				ID:        lock.ID,
-				Name:      lock.Name,
-				LockLevel: lockLevel,
+				}).WithSystemData(lock.SystemData),
+				client:     client,
+				ID:         lock.ID,
+				Name:       lock.Name,
+				LockLevel:  lockLevel,
+				Scope:      scope,
+				ScopeLevel: scopeLevel,
 			})
 		}
 	}
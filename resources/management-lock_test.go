@@ -0,0 +1,59 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestManagementLockListAndRemove(t *testing.T) {
+	rgPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-dev"
+	vnetLockPath := rgPath + "/providers/Microsoft.Network/virtualNetworks/vnet-dev/providers/Microsoft.Authorization/locks/vnet-lock"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, rgPath+"/providers/Microsoft.Authorization/locks", http.StatusOK,
+		"management-lock-list.json")
+	server.Respond(http.MethodDelete, vnetLockPath, http.StatusOK, nil)
+
+	lister := ManagementLockLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-dev",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	rgLock := resources[0].(*ManagementLock)
+	assert.Equal(t, "rg-lock", rgLock.String())
+	assert.Equal(t, "ResourceGroup", rgLock.Properties().Get("ScopeLevel"))
+
+	vnetLock := resources[1].(*ManagementLock)
+	assert.Equal(t, "Resource", vnetLock.Properties().Get("ScopeLevel"))
+	assert.Equal(t, "ReadOnly", vnetLock.Properties().Get("LockLevel"))
+
+	assert.NoError(t, vnetLock.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, vnetLockPath), 1)
+}
+
+func TestSubscriptionManagementLockList(t *testing.T) {
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet,
+		"/subscriptions/"+azuretest.SubscriptionID+"/providers/Microsoft.Authorization/locks", http.StatusOK,
+		"management-lock-list.json")
+
+	lister := SubscriptionManagementLockLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "sub-lock", resources[0].(*SubscriptionManagementLock).String())
+}
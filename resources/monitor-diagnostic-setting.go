@@ -55,7 +55,7 @@ func (l MonitorDiagnosticSettingLister) List(ctx context.Context, o interface{})
 
 	log := logrus.WithField("r", MonitorDiagnosticSettingResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armmonitor.NewDiagnosticSettingsClient(opts.Authorizers.IdentityCreds, nil)
+	client, err := armmonitor.NewDiagnosticSettingsClient(opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -76,10 +76,10 @@ func (l MonitorDiagnosticSettingLister) List(ctx context.Context, o interface{})
 		if page.Value != nil {
 			for _, ds := range page.Value {
 				resources = append(resources, &MonitorDiagnosticSetting{
-					BaseResource: &BaseResource{
+					BaseResource: (&BaseResource{
 						Region:         ptr.String("global"),
 						SubscriptionID: &opts.SubscriptionID,
-					},
+					}).WithSystemData(ds.SystemData),
 					client: client,
 					Name:   ds.Name,
 				})
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -24,6 +25,8 @@ func init() {
 		Resource: &NetworkInterface{},
 		Lister:   &NetworkInterfaceLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/networkInterfaces", NetworkInterfaceResource)
 }
 
 type NetworkInterfaceLister struct{}
@@ -38,7 +41,7 @@ func (l NetworkInterfaceLister) List(ctx context.Context, o interface{}) ([]reso
 
 	resources := make([]resource.Resource, 0)
 
-	client, err := armnetwork.NewInterfacesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewInterfacesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
@@ -54,11 +57,11 @@ func (l NetworkInterfaceLister) List(ctx context.Context, o interface{}) ([]reso
 
 		for _, entity := range page.Value {
 			resources = append(resources, &NetworkInterface{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
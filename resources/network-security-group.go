@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &NetworkSecurityGroup{},
 		Lister:   &NetworkSecurityGroupLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/networkSecurityGroups", NetworkSecurityGroupResource)
 }
 
 type NetworkSecurityGroup struct {
@@ -58,7 +62,7 @@ func (l NetworkSecurityGroupLister) List(ctx context.Context, o interface{}) ([]
 
 	log := logrus.WithField("r", NetworkSecurityGroupResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armnetwork.NewSecurityGroupsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewSecurityGroupsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -76,10 +80,11 @@ func (l NetworkSecurityGroupLister) List(ctx context.Context, o interface{}) ([]
 
 		for _, entity := range page.Value {
 			resources = append(resources, &NetworkSecurityGroup{
-				BaseResource: &BaseResource{
-					Region:        entity.Location,
-					ResourceGroup: &opts.ResourceGroup,
-				},
+				BaseResource: (&BaseResource{
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         entity.Location,
+					ResourceGroup:  &opts.ResourceGroup,
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -8,8 +8,6 @@ import (
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armpolicy"
 
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -66,12 +64,10 @@ func (l PolicyAssignmentLister) List(ctx context.Context, o interface{}) ([]reso
 
 	log := logrus.WithField("r", PolicyAssignmentResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armpolicy.NewAssignmentsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds,
-		&arm.ClientOptions{
-			ClientOptions: azcore.ClientOptions{
-				APIVersion: "2024-04-01",
-			},
-		})
+	clientOptions := opts.Authorizers.ARMClientOptions()
+	clientOptions.APIVersion = "2024-04-01"
+
+	client, err := armpolicy.NewAssignmentsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, clientOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -102,9 +98,10 @@ func (l PolicyAssignmentLister) List(ctx context.Context, o interface{}) ([]reso
 			}
 
 			resources = append(resources, &PolicyAssignment{
-				BaseResource: &BaseResource{
-					Region: ptr.String("global"),
-				},
+				BaseResource: (&BaseResource{
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         ptr.String("global"),
+				}).WithSystemData(g.SystemData),
 				client:          client,
 				Name:            ptr.ToString(g.Name),
 				Scope:           scope,
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestPolicyAssignmentListFilterAndRemove(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.Authorization/policyAssignments"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path, http.StatusOK, "policy-assignment-list.json")
+	server.Respond(http.MethodDelete, path+"/require-tags", http.StatusOK, nil)
+
+	lister := PolicyAssignmentLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	custom := resources[0].(*PolicyAssignment)
+	assert.Equal(t, "require-tags", custom.String())
+	assert.Equal(t, "Default", custom.Properties().Get("EnforcementMode"))
+	assert.Equal(t, "alice@example.com", custom.Properties().Get("CreatedBy"))
+	assert.Equal(t, "User", custom.Properties().Get("CreatedByType"))
+	assert.Equal(t, "2024-01-15T10:30:00Z", custom.Properties().Get("CreatedAt"))
+	assert.Equal(t, "2024-02-01T08:00:00Z", custom.Properties().Get("LastModifiedAt"))
+	assert.NoError(t, custom.Filter())
+
+	builtin := resources[1].(*PolicyAssignment)
+	assert.EqualError(t, builtin.Filter(), "cannot remove built-in policy")
+
+	assert.NoError(t, custom.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, path+"/require-tags"), 1)
+}
+
+func TestPolicyAssignmentCountsForSubscriptionLimit(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.Authorization/policyAssignments"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path, http.StatusOK, "policy-assignment-list.json")
+
+	opts := &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	}
+
+	lister := PolicyAssignmentLister{}
+	resources, err := lister.List(context.TODO(), opts)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	// Note: the items are enqueued the way the scanner of libnuke does
+	q := queue.New()
+	for _, r := range resources {
+		item := &queue.Item{Resource: r, State: queue.ItemStateNew, Type: PolicyAssignmentResource, Opts: opts}
+		r.(*PolicyAssignment).BeforeEnqueue(item)
+		q.Items = append(q.Items, item)
+	}
+
+	assert.Equal(t, map[string]int{azuretest.SubscriptionID: 2}, azure.CountBySubscription(azure.RemovableItems(q)))
+	assert.ErrorContains(t, (&azure.RemovalLimits{MaxRemovalsPerSubscription: 1}).Check(q),
+		"refusing to remove 2 resources from subscription "+azuretest.SubscriptionID)
+}
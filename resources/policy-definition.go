@@ -6,8 +6,6 @@ import (
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armpolicy"
 
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -57,12 +55,10 @@ func (l PolicyDefinitionLister) List(ctx context.Context, o interface{}) ([]reso
 
 	log := logrus.WithField("r", PolicyDefinitionResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armpolicy.NewDefinitionsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds,
-		&arm.ClientOptions{
-			ClientOptions: azcore.ClientOptions{
-				APIVersion: "2023-04-01",
-			},
-		})
+	clientOptions := opts.Authorizers.ARMClientOptions()
+	clientOptions.APIVersion = "2023-04-01"
+
+	client, err := armpolicy.NewDefinitionsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, clientOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -103,9 +99,10 @@ func (l PolicyDefinitionLister) List(ctx context.Context, o interface{}) ([]reso
 			}
 
 			resources = append(resources, &PolicyDefinition{
-				BaseResource: &BaseResource{
-					Region: ptr.String("global"),
-				},
+				BaseResource: (&BaseResource{
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         ptr.String("global"),
+				}).WithSystemData(g.SystemData),
 				client:      client,
 				Name:        g.Name,
 				DisplayName: displayName,
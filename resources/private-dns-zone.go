@@ -24,6 +24,8 @@ func init() {
 		Resource: &PrivateDNSZone{},
 		Lister:   &PrivateDNSZoneLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/privateDnsZones", PrivateDNSZoneResource)
 }
 
 type PrivateDNSZone struct {
@@ -65,7 +67,8 @@ func (l PrivateDNSZoneLister) List(ctx context.Context, o interface{}) ([]resour
 
 	log.Trace("start")
 
-	client, err := armprivatedns.NewPrivateZonesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armprivatedns.NewPrivateZonesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -81,11 +84,11 @@ func (l PrivateDNSZoneLister) List(ctx context.Context, o interface{}) ([]resour
 
 		for _, entity := range page.Value {
 			resources = append(resources, &PrivateDNSZone{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  azure.GetResourceGroupFromID(*entity.ID),
 					SubscriptionID: ptr.String(opts.SubscriptionID),
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
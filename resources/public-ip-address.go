@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -26,6 +28,8 @@ func init() {
 			"PublicIPAddresses",
 		},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/publicIPAddresses", PublicIPAddressesResource)
 }
 
 type PublicIPAddresses struct {
@@ -61,7 +65,8 @@ func (l PublicIPAddressesLister) List(ctx context.Context, o interface{}) ([]res
 
 	log := logrus.WithField("r", PublicIPAddressesResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armnetwork.NewPublicIPAddressesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewPublicIPAddressesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -79,11 +84,11 @@ func (l PublicIPAddressesLister) List(ctx context.Context, o interface{}) ([]res
 
 		for _, entity := range page.Value {
 			resources = append(resources, &PublicIPAddresses{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -78,17 +78,20 @@ func (l RecoveryServicesBackupPolicyLister) List(ctx context.Context, o interfac
 
 	log.Trace("creating client")
 
-	vaultsClient, err := armrecoveryservices.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	vaultsClient, err := armrecoveryservices.NewVaultsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
 
-	backupClient, err := armrecoveryservicesbackup.NewBackupPoliciesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	backupClient, err := armrecoveryservicesbackup.NewBackupPoliciesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
 
-	protectionsClient, err := armrecoveryservicesbackup.NewProtectionPoliciesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	protectionsClient, err := armrecoveryservicesbackup.NewProtectionPoliciesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -3,16 +3,11 @@ package resources
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservices"
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservicesbackup"
-	"github.com/Azure/go-autorest/autorest/to"
-
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -34,25 +29,30 @@ func init() {
 type RecoveryServicesBackupProtectedItem struct {
 	*BaseResource `property:",inline"`
 
-	client     *armrecoveryservicesbackup.BackupProtectedItemsClient
-	itemClient *armrecoveryservicesbackup.ProtectedItemsClient
+	backup *recoveryServicesBackup
+	item   *backupItem
 
 	ID            *string
 	Name          *string
 	VaultName     *string
 	ContainerName *string
-	backupFabric  *string
+	SoftDeleted   bool `description:"Whether the item is soft-deleted and retained until its deferred delete time."`
 }
 
+// Filter filters soft-deleted items, restoring them would require soft delete of the vault to be disabled, which is
+// only done by the teardown of the vault itself. They are purged after the retention period of the vault.
 func (r *RecoveryServicesBackupProtectedItem) Filter() error {
+	if r.SoftDeleted {
+		return fmt.Errorf("soft-deleted, purged after the retention period or by the teardown of the vault")
+	}
+
 	return nil
 }
 
+// Remove stops the protection of the item and deletes its backup data. The security settings of the vault are left
+// as they are, with soft delete enabled the backup data is retained until the retention period of the vault ends.
 func (r *RecoveryServicesBackupProtectedItem) Remove(ctx context.Context) error {
-	_, err := r.itemClient.Delete(
-		ctx, to.String(r.VaultName), to.String(r.ResourceGroup),
-		to.String(r.backupFabric), to.String(r.ContainerName), to.String(r.Name), nil)
-	return err
+	return r.backup.deleteItem(ctx, ptr.ToString(r.ResourceGroup), ptr.ToString(r.VaultName), r.item)
 }
 
 func (r *RecoveryServicesBackupProtectedItem) Properties() types.Properties {
@@ -79,63 +79,41 @@ func (l RecoveryServicesBackupProtectedItemLister) List(ctx context.Context, o i
 		WithField("rg", opts.ResourceGroup)
 
 	log.Trace("creating client")
-	vaultsClient, err := armrecoveryservices.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
-	if err != nil {
-		return resources, err
-	}
-
-	client, err := armrecoveryservicesbackup.NewBackupProtectedItemsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
-	if err != nil {
-		return resources, err
-	}
-
-	protectedItems, err := armrecoveryservicesbackup.NewProtectedItemsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	backup, err := newRecoveryServicesBackup(opts)
 	if err != nil {
 		return resources, err
 	}
 
 	log.Trace("listing resources")
 
-	vaultsPager := vaultsClient.NewListByResourceGroupPager(opts.ResourceGroup, nil)
+	vaultsPager := backup.vaults.NewListByResourceGroupPager(opts.ResourceGroup, nil)
 	for vaultsPager.More() {
-		log.Trace("not done")
 		page, err := vaultsPager.NextPage(ctx)
 		if err != nil {
 			return resources, err
 		}
 
 		for _, v := range page.Value {
-			itemPager := client.NewListPager(to.String(v.Name), opts.ResourceGroup, nil)
-			for itemPager.More() {
-				page, err := itemPager.NextPage(ctx)
-				if err != nil {
-					return resources, err
-				}
-
-				for _, i := range page.Value {
-					containerName := to.String(i.Properties.GetProtectedItem().ContainerName)
-					if to.String(i.Properties.GetProtectedItem().ProtectedItemType) == "Microsoft.Compute/virtualMachines" {
-						if !strings.HasPrefix(containerName, "IaasVMContainer;") {
-							containerName = fmt.Sprintf("IaasVMContainer;%s", containerName)
-						}
-					}
-
-					resources = append(resources, &RecoveryServicesBackupProtectedItem{
-						BaseResource: &BaseResource{
-							Region:         i.Location,
-							ResourceGroup:  &opts.ResourceGroup,
-							SubscriptionID: &opts.SubscriptionID,
-						},
-						client:     client,
-						itemClient: protectedItems,
-						VaultName:  v.Name,
-						ID:         i.ID,
-						Name:       i.Name,
-
-						ContainerName: to.StringPtr(containerName),
-						backupFabric:  to.StringPtr("Azure"), // TODO: this should be calculated
-					})
-				}
+			items, err := backup.listItems(ctx, opts.ResourceGroup, ptr.ToString(v.Name))
+			if err != nil {
+				return resources, err
+			}
+
+			for _, i := range items {
+				resources = append(resources, &RecoveryServicesBackupProtectedItem{
+					BaseResource: &BaseResource{
+						Region:         v.Location,
+						ResourceGroup:  &opts.ResourceGroup,
+						SubscriptionID: &opts.SubscriptionID,
+					},
+					backup:        backup,
+					item:          i,
+					VaultName:     v.Name,
+					ID:            ptr.String(i.ID),
+					Name:          ptr.String(i.Name),
+					ContainerName: ptr.String(i.Container),
+					SoftDeleted:   i.SoftDeleted,
+				})
 			}
 		}
 	}
@@ -75,19 +75,19 @@ func (l RecoveryServicesBackupProtectionContainersLister) List(ctx context.Conte
 	log.Trace("creating client")
 
 	vaultsClient, err := armrecoveryservices.NewVaultsClient(
-		opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
 
 	client, err := armrecoveryservicesbackup.NewBackupProtectionContainersClient(
-		opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
 
 	protectedContainers, err := armrecoveryservicesbackup.NewProtectionContainersClient(
-		opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
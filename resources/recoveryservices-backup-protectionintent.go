@@ -73,17 +73,20 @@ func (l RecoveryServicesBackupProtectionIntentLister) List(ctx context.Context,
 
 	log.Trace("creating client")
 
-	vaultsClient, err := armrecoveryservices.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	vaultsClient, err := armrecoveryservices.NewVaultsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
 
-	client, err := armrecoveryservicesbackup.NewBackupProtectionIntentClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armrecoveryservicesbackup.NewBackupProtectionIntentClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
 
-	protectedContainers, err := armrecoveryservicesbackup.NewProtectionIntentClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	protectedContainers, err := armrecoveryservicesbackup.NewProtectionIntentClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return resources, err
 	}
@@ -108,8 +111,9 @@ func (l RecoveryServicesBackupProtectionIntentLister) List(ctx context.Context,
 				for _, i := range page.Value {
 					resources = append(resources, &RecoveryServicesBackupProtectionIntent{
 						BaseResource: &BaseResource{
-							Region:        i.Location,
-							ResourceGroup: to.StringPtr(opts.ResourceGroup),
+							SubscriptionID: &opts.SubscriptionID,
+							Region:         i.Location,
+							ResourceGroup:  to.StringPtr(opts.ResourceGroup),
 						},
 						client:       client,
 						pClient:      protectedContainers,
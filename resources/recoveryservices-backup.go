@@ -0,0 +1,277 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservices"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservicesbackup"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+// recoveryServicesBackup bundles the clients that are needed to tear down the backup data of a recovery services
+// vault, it is shared by the vault and the backup resources.
+type recoveryServicesBackup struct {
+	vaults         *armrecoveryservices.VaultsClient
+	configs        *armrecoveryservicesbackup.BackupResourceVaultConfigsClient
+	items          *armrecoveryservicesbackup.BackupProtectedItemsClient
+	protectedItems *armrecoveryservicesbackup.ProtectedItemsClient
+	containers     *armrecoveryservicesbackup.BackupProtectionContainersClient
+	protection     *armrecoveryservicesbackup.ProtectionContainersClient
+}
+
+// backupItem is a protected item of a vault, including items that are soft-deleted
+type backupItem struct {
+	ID               string
+	Name             string
+	Fabric           string
+	Container        string
+	Type             *string
+	SourceResourceID *string
+	SoftDeleted      bool
+}
+
+// backupContainer is a protection container of a vault that has to be unregistered before the vault can be removed
+type backupContainer struct {
+	ID     string
+	Name   string
+	Fabric string
+}
+
+// unregisteredManagementTypes are the backup management types whose containers are registered with the vault,
+// containers of virtual machines are removed together with their protected items.
+var unregisteredManagementTypes = []armrecoveryservicesbackup.BackupManagementType{
+	armrecoveryservicesbackup.BackupManagementTypeAzureStorage,
+	armrecoveryservicesbackup.BackupManagementTypeAzureWorkload,
+}
+
+func newRecoveryServicesBackup(opts *azure.ListerOpts) (*recoveryServicesBackup, error) {
+	creds := opts.Authorizers.IdentityCreds
+
+	vaults, err := armrecoveryservices.NewVaultsClient(opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := armrecoveryservicesbackup.NewBackupResourceVaultConfigsClient(
+		opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := armrecoveryservicesbackup.NewBackupProtectedItemsClient(
+		opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	protectedItems, err := armrecoveryservicesbackup.NewProtectedItemsClient(
+		opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := armrecoveryservicesbackup.NewBackupProtectionContainersClient(
+		opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	protection, err := armrecoveryservicesbackup.NewProtectionContainersClient(
+		opts.SubscriptionID, creds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	return &recoveryServicesBackup{
+		vaults:         vaults,
+		configs:        configs,
+		items:          items,
+		protectedItems: protectedItems,
+		containers:     containers,
+		protection:     protection,
+	}, nil
+}
+
+// disableSecurityFeatures disables immutability, soft delete and enhanced security of the vault, so that backup data
+// is removed right away instead of being retained.
+func (b *recoveryServicesBackup) disableSecurityFeatures(ctx context.Context, resourceGroup, vaultName string) error {
+	vault, err := b.vaults.Get(ctx, resourceGroup, vaultName, nil)
+	if err != nil {
+		return err
+	}
+
+	var settings armrecoveryservices.SecuritySettings
+	if vault.Properties != nil && vault.Properties.SecuritySettings != nil {
+		settings = *vault.Properties.SecuritySettings
+	}
+
+	if settings.SoftDeleteSettings != nil && settings.SoftDeleteSettings.SoftDeleteState != nil &&
+		*settings.SoftDeleteSettings.SoftDeleteState == armrecoveryservices.SoftDeleteStateAlwaysON {
+		return fmt.Errorf("soft delete of the vault is always on and cannot be disabled")
+	}
+
+	if settings.ImmutabilitySettings != nil && settings.ImmutabilitySettings.State != nil {
+		switch *settings.ImmutabilitySettings.State {
+		case armrecoveryservices.ImmutabilityStateLocked:
+			return fmt.Errorf("immutability of the vault is locked and cannot be disabled")
+		case armrecoveryservices.ImmutabilityStateUnlocked:
+			if err := b.disableImmutability(ctx, resourceGroup, vaultName); err != nil {
+				return err
+			}
+		}
+	}
+
+	config, err := b.configs.Get(ctx, vaultName, resourceGroup, nil)
+	if err != nil {
+		return err
+	}
+
+	softDeleteDisabled := armrecoveryservicesbackup.SoftDeleteFeatureStateDisabled
+	enhancedSecurityDisabled := armrecoveryservicesbackup.EnhancedSecurityStateDisabled
+
+	if properties := config.Properties; properties != nil &&
+		properties.SoftDeleteFeatureState != nil && *properties.SoftDeleteFeatureState == softDeleteDisabled &&
+		properties.EnhancedSecurityState != nil && *properties.EnhancedSecurityState == enhancedSecurityDisabled {
+		return nil
+	}
+
+	_, err = b.configs.Update(ctx, vaultName, resourceGroup, armrecoveryservicesbackup.BackupResourceVaultConfigResource{
+		Properties: &armrecoveryservicesbackup.BackupResourceVaultConfig{
+			SoftDeleteFeatureState: &softDeleteDisabled,
+			EnhancedSecurityState:  &enhancedSecurityDisabled,
+		},
+	}, nil)
+	return err
+}
+
+func (b *recoveryServicesBackup) disableImmutability(ctx context.Context, resourceGroup, vaultName string) error {
+	disabled := armrecoveryservices.ImmutabilityStateDisabled
+
+	poller, err := b.vaults.BeginUpdate(ctx, resourceGroup, vaultName, armrecoveryservices.PatchVault{
+		Properties: &armrecoveryservices.VaultProperties{
+			SecuritySettings: &armrecoveryservices.SecuritySettings{
+				ImmutabilitySettings: &armrecoveryservices.ImmutabilitySettings{
+					State: &disabled,
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	return err
+}
+
+// listItems returns all protected items of the vault, including the soft-deleted items
+func (b *recoveryServicesBackup) listItems(ctx context.Context, resourceGroup, vaultName string) ([]*backupItem, error) {
+	items := make([]*backupItem, 0)
+
+	pager := b.items.NewListPager(vaultName, resourceGroup, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range page.Value {
+			fabric, container := parseBackupID(ptr.ToString(i.ID))
+
+			item := &backupItem{
+				ID:        ptr.ToString(i.ID),
+				Name:      ptr.ToString(i.Name),
+				Fabric:    fabric,
+				Container: container,
+			}
+
+			if i.Properties != nil {
+				properties := i.Properties.GetProtectedItem()
+				item.Type = properties.ProtectedItemType
+				item.SourceResourceID = properties.SourceResourceID
+				item.SoftDeleted = ptr.ToBool(properties.IsScheduledForDeferredDelete)
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// undeleteItem restores a soft-deleted item, so that it can be deleted permanently
+func (b *recoveryServicesBackup) undeleteItem(
+	ctx context.Context, resourceGroup, vaultName string, item *backupItem) error {
+	_, err := b.protectedItems.CreateOrUpdate(ctx, vaultName, resourceGroup, item.Fabric, item.Container, item.Name,
+		armrecoveryservicesbackup.ProtectedItemResource{
+			Properties: &armrecoveryservicesbackup.ProtectedItem{
+				ProtectedItemType: item.Type,
+				SourceResourceID:  item.SourceResourceID,
+				IsRehydrate:       ptr.Bool(true),
+			},
+		}, nil)
+	return err
+}
+
+// deleteItem stops the protection of the item and deletes its backup data
+func (b *recoveryServicesBackup) deleteItem(ctx context.Context, resourceGroup, vaultName string, item *backupItem) error {
+	_, err := b.protectedItems.Delete(ctx, vaultName, resourceGroup, item.Fabric, item.Container, item.Name, nil)
+	return err
+}
+
+// listContainers returns the containers that are registered with the vault
+func (b *recoveryServicesBackup) listContainers(
+	ctx context.Context, resourceGroup, vaultName string) ([]*backupContainer, error) {
+	containers := make([]*backupContainer, 0)
+
+	for _, managementType := range unregisteredManagementTypes {
+		pager := b.containers.NewListPager(vaultName, resourceGroup,
+			&armrecoveryservicesbackup.BackupProtectionContainersClientListOptions{
+				Filter: ptr.String(fmt.Sprintf("backupManagementType eq '%s'", managementType)),
+			})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, c := range page.Value {
+				fabric, _ := parseBackupID(ptr.ToString(c.ID))
+				containers = append(containers, &backupContainer{
+					ID:     ptr.ToString(c.ID),
+					Name:   ptr.ToString(c.Name),
+					Fabric: fabric,
+				})
+			}
+		}
+	}
+
+	return containers, nil
+}
+
+// unregisterContainer unregisters the container from the vault
+func (b *recoveryServicesBackup) unregisterContainer(
+	ctx context.Context, resourceGroup, vaultName string, container *backupContainer) error {
+	_, err := b.protection.Unregister(ctx, vaultName, resourceGroup, container.Fabric, container.Name, nil)
+	return err
+}
+
+// parseBackupID returns the backup fabric and the protection container from the ID of a protected item or container
+func parseBackupID(id string) (fabric, container string) {
+	parts := strings.Split(id, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		switch strings.ToLower(parts[i]) {
+		case "backupfabrics":
+			fabric = parts[i+1]
+		case "protectioncontainers":
+			container = parts[i+1]
+		}
+	}
+
+	return fabric, container
+}
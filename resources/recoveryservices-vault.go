@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gotidy/ptr"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/recoveryservices/armrecoveryservices"
 
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
@@ -28,25 +30,171 @@ func init() {
 			RecoveryServicesBackupProtectedItemResource,
 		},
 	})
+
+	azure.RegisterARMType("Microsoft.RecoveryServices/vaults", RecoveryServicesVaultResource)
 }
 
+const (
+	// recoveryServicesVaultStages is the number of stages of the teardown of a vault, the last stage removes the vault
+	recoveryServicesVaultStages = 5
+
+	// recoveryServicesVaultRequestTimeout is the time after which an operation of the teardown that had no effect is
+	// requested again, Azure may drop or reject the asynchronous operations
+	recoveryServicesVaultRequestTimeout = 10 * time.Minute
+
+	// recoveryServicesVaultMaxRequests is the number of times an operation is requested before the teardown fails
+	recoveryServicesVaultMaxRequests = 3
+)
+
 type RecoveryServicesVault struct {
 	*BaseResource `property:",inline"`
 
 	client *armrecoveryservices.VaultsClient
+	backup *recoveryServicesBackup
 	ID     *string
 	Name   *string
+
+	// securityDisabled is set once soft delete, enhanced security and immutability are disabled
+	securityDisabled bool
+
+	// requested tracks the undeletes, deletes and unregistrations that were already requested, they are processed
+	// asynchronously by Azure and are only requested again once they had no effect for a while.
+	requested map[string]*vaultRequest
+
+	// now returns the current time, it is replaced by tests
+	now func() time.Time
+}
+
+// vaultRequest is an operation of the teardown of a vault that was requested
+type vaultRequest struct {
+	at    time.Time
+	count int
 }
 
 func (r *RecoveryServicesVault) Filter() error {
 	return nil
 }
 
+// Remove tears down the vault in stages, a vault cannot be removed while it still contains backup data. Each call
+// runs the next stage and holds the queue item with the stage as reason until the vault itself can be removed.
 func (r *RecoveryServicesVault) Remove(ctx context.Context) error {
-	_, err := r.client.Delete(ctx, *r.ResourceGroup, *r.Name, nil)
+	stage, err := r.teardown(ctx)
+	if err != nil {
+		return err
+	}
+
+	if stage != "" {
+		return liberrors.ErrHoldResource(stage)
+	}
+
+	_, err = r.client.Delete(ctx, *r.ResourceGroup, *r.Name, nil)
 	return err
 }
 
+// teardown runs the next stage of the teardown of the vault and returns its description, an empty description is
+// returned when only the removal of the vault is left.
+func (r *RecoveryServicesVault) teardown(ctx context.Context) (string, error) { //nolint:gocyclo
+	resourceGroup, vaultName := ptr.ToString(r.ResourceGroup), ptr.ToString(r.Name)
+
+	if r.requested == nil {
+		r.requested = make(map[string]*vaultRequest)
+	}
+
+	if !r.securityDisabled {
+		if err := r.backup.disableSecurityFeatures(ctx, resourceGroup, vaultName); err != nil {
+			return "", err
+		}
+
+		r.securityDisabled = true
+
+		return r.stage(1, "disabled soft delete, enhanced security and immutability"), nil
+	}
+
+	items, err := r.backup.listItems(ctx, resourceGroup, vaultName)
+	if err != nil {
+		return "", err
+	}
+
+	softDeleted := 0
+	for _, item := range items {
+		if !item.SoftDeleted {
+			continue
+		}
+
+		softDeleted++
+		if err := r.request("undelete:"+item.ID, func() error {
+			return r.backup.undeleteItem(ctx, resourceGroup, vaultName, item)
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	if softDeleted > 0 {
+		return r.stage(2, fmt.Sprintf("undeleting %d soft-deleted backup items", softDeleted)), nil
+	}
+
+	if len(items) > 0 {
+		for _, item := range items {
+			if err := r.request("delete:"+item.ID, func() error {
+				return r.backup.deleteItem(ctx, resourceGroup, vaultName, item)
+			}); err != nil {
+				return "", err
+			}
+		}
+
+		return r.stage(3, fmt.Sprintf("stopping protection and deleting backup data of %d items", len(items))), nil
+	}
+
+	containers, err := r.backup.listContainers(ctx, resourceGroup, vaultName)
+	if err != nil {
+		return "", err
+	}
+
+	if len(containers) > 0 {
+		for _, container := range containers {
+			if err := r.request("unregister:"+container.ID, func() error {
+				return r.backup.unregisterContainer(ctx, resourceGroup, vaultName, container)
+			}); err != nil {
+				return "", err
+			}
+		}
+
+		return r.stage(4, fmt.Sprintf("unregistering %d containers", len(containers))), nil
+	}
+
+	return "", nil
+}
+
+// request sends the operation if it was not requested before, or if it was requested too long ago without effect.
+// An error is returned when the operation had no effect after it was requested the maximum number of times, so that
+// the vault fails instead of being held forever.
+func (r *RecoveryServicesVault) request(operation string, send func() error) error {
+	if r.now == nil {
+		r.now = time.Now
+	}
+
+	req, ok := r.requested[operation]
+	if ok && r.now().Sub(req.at) < recoveryServicesVaultRequestTimeout {
+		return nil
+	}
+
+	if !ok {
+		req = &vaultRequest{}
+		r.requested[operation] = req
+	} else if req.count >= recoveryServicesVaultMaxRequests {
+		return fmt.Errorf("%s had no effect after it was requested %d times", operation, req.count)
+	}
+
+	req.at = r.now()
+	req.count++
+
+	return send()
+}
+
+func (r *RecoveryServicesVault) stage(stage int, description string) string {
+	return fmt.Sprintf("teardown %d/%d: %s", stage, recoveryServicesVaultStages, description)
+}
+
 func (r *RecoveryServicesVault) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -70,7 +218,13 @@ func (l RecoveryServicesVaultLister) List(ctx context.Context, o interface{}) ([
 
 	log.Trace("creating client")
 
-	client, err := armrecoveryservices.NewVaultsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armrecoveryservices.NewVaultsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := newRecoveryServicesBackup(opts)
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +242,13 @@ func (l RecoveryServicesVaultLister) List(ctx context.Context, o interface{}) ([
 
 		for _, item := range page.Value {
 			resources = append(resources, &RecoveryServicesVault{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         item.Location,
 					ResourceGroup:  ptr.String(opts.ResourceGroup),
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithSystemData(item.SystemData),
 				client: client,
+				backup: backup,
 				ID:     item.ID,
 				Name:   item.Name,
 			})
@@ -0,0 +1,191 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestRecoveryServicesVaultTeardown(t *testing.T) { //nolint:funlen
+	vaultsPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-dev/providers/Microsoft.RecoveryServices/vaults"
+	vaultPath := vaultsPath + "/vault-dev"
+	itemPath := vaultPath + "/backupFabrics/Azure/protectionContainers/IaasVMContainer;iaasvmcontainerv2;rg-dev;vm-dev" +
+		"/protectedItems/VM;iaasvmcontainerv2;rg-dev;vm-dev"
+	containerPath := vaultPath + "/backupFabrics/Azure/protectionContainers/StorageContainer;storage;rg-dev;stdev"
+
+	vault := map[string]interface{}{
+		"id":       vaultPath,
+		"name":     "vault-dev",
+		"location": "eastus",
+		"properties": map[string]interface{}{
+			"securitySettings": map[string]interface{}{
+				"immutabilitySettings": map[string]string{"state": "Unlocked"},
+			},
+		},
+	}
+
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, vaultsPath, http.StatusOK, map[string]interface{}{
+		"value": []interface{}{vault},
+	})
+	server.Respond(http.MethodGet, vaultPath, http.StatusOK, vault)
+	server.Respond(http.MethodPatch, vaultPath, http.StatusOK, vault)
+	server.Respond(http.MethodGet, vaultPath+"/backupconfig/vaultconfig", http.StatusOK, map[string]interface{}{
+		"properties": map[string]string{"softDeleteFeatureState": "Enabled", "enhancedSecurityState": "Enabled"},
+	})
+	server.Respond(http.MethodPatch, vaultPath+"/backupconfig/vaultconfig", http.StatusOK, map[string]interface{}{})
+
+	// the item is soft-deleted until it is undeleted, and gone once it is deleted
+	softDeleted, deleted := true, false
+	server.Handle(http.MethodGet, vaultPath+"/backupProtectedItems", func(w http.ResponseWriter, _ *http.Request) {
+		items := make([]interface{}, 0)
+		if !deleted {
+			items = append(items, map[string]interface{}{
+				"id":   itemPath,
+				"name": "VM;iaasvmcontainerv2;rg-dev;vm-dev",
+				"properties": map[string]interface{}{
+					"protectedItemType": "Microsoft.Compute/virtualMachines",
+					"sourceResourceId": "/subscriptions/" + azuretest.SubscriptionID +
+						"/resourceGroups/rg-dev/providers/Microsoft.Compute/virtualMachines/vm-dev",
+					"isScheduledForDeferredDelete": softDeleted,
+				},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"value": items})
+	})
+	server.Handle(http.MethodPut, itemPath, func(w http.ResponseWriter, _ *http.Request) {
+		softDeleted = false
+		w.WriteHeader(http.StatusAccepted)
+	})
+	server.Handle(http.MethodDelete, itemPath, func(w http.ResponseWriter, _ *http.Request) {
+		deleted = true
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	unregistered := false
+	server.Handle(http.MethodGet, vaultPath+"/backupProtectionContainers", func(w http.ResponseWriter, r *http.Request) {
+		containers := make([]interface{}, 0)
+		if !unregistered && r.URL.Query().Get("$filter") == "backupManagementType eq 'AzureStorage'" {
+			containers = append(containers, map[string]interface{}{
+				"id":   containerPath,
+				"name": "StorageContainer;storage;rg-dev;stdev",
+			})
+		}
+		writeJSON(w, map[string]interface{}{"value": containers})
+	})
+	server.Handle(http.MethodDelete, containerPath, func(w http.ResponseWriter, _ *http.Request) {
+		unregistered = true
+		w.WriteHeader(http.StatusAccepted)
+	})
+	server.Respond(http.MethodDelete, vaultPath, http.StatusOK, nil)
+
+	lister := RecoveryServicesVaultLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-dev",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	r := resources[0].(*RecoveryServicesVault)
+
+	stages := []string{
+		"teardown 1/5: disabled soft delete, enhanced security and immutability",
+		"teardown 2/5: undeleting 1 soft-deleted backup items",
+		"teardown 3/5: stopping protection and deleting backup data of 1 items",
+		"teardown 4/5: unregistering 1 containers",
+	}
+	for _, stage := range stages {
+		err := r.Remove(context.TODO())
+
+		var holdErr liberrors.ErrHoldResource
+		require.True(t, errors.As(err, &holdErr), "expected hold for %q, got %v", stage, err)
+		assert.Equal(t, stage, holdErr.Error())
+	}
+
+	assert.NoError(t, r.Remove(context.TODO()))
+
+	assert.Len(t, server.Requests(http.MethodPatch, vaultPath), 1)
+	assert.Len(t, server.Requests(http.MethodPatch, vaultPath+"/backupconfig/vaultconfig"), 1)
+	assert.Len(t, server.Requests(http.MethodPut, itemPath), 1)
+	assert.Len(t, server.Requests(http.MethodDelete, itemPath), 1)
+	assert.Len(t, server.Requests(http.MethodDelete, containerPath), 1)
+	assert.Len(t, server.Requests(http.MethodDelete, vaultPath), 1)
+}
+
+func TestRecoveryServicesVaultTeardownRequestsAgain(t *testing.T) {
+	vaultPath := "/subscriptions/" + azuretest.SubscriptionID + "/resourceGroups/rg-dev/providers/Microsoft.RecoveryServices/vaults/vault-dev"
+	itemPath := vaultPath + "/backupFabrics/Azure/protectionContainers/IaasVMContainer;iaasvmcontainerv2;rg-dev;vm-dev" +
+		"/protectedItems/VM;iaasvmcontainerv2;rg-dev;vm-dev"
+
+	// the delete of the item is accepted, but the item is never removed
+	server := azuretest.NewServer(t)
+	server.Respond(http.MethodGet, vaultPath+"/backupProtectedItems", http.StatusOK, map[string]interface{}{
+		"value": []interface{}{map[string]interface{}{
+			"id":         itemPath,
+			"name":       "VM;iaasvmcontainerv2;rg-dev;vm-dev",
+			"properties": map[string]interface{}{"protectedItemType": "Microsoft.Compute/virtualMachines"},
+		}},
+	})
+	server.Respond(http.MethodDelete, itemPath, http.StatusAccepted, nil)
+
+	backup, err := newRecoveryServicesBackup(&azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-dev",
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	r := &RecoveryServicesVault{
+		BaseResource:     &BaseResource{ResourceGroup: ptr.String("rg-dev")},
+		backup:           backup,
+		Name:             ptr.String("vault-dev"),
+		securityDisabled: true,
+		now:              func() time.Time { return now },
+	}
+
+	remove := func() error {
+		err := r.Remove(context.TODO())
+
+		var holdErr liberrors.ErrHoldResource
+		if errors.As(err, &holdErr) {
+			return nil
+		}
+
+		return err
+	}
+
+	// the delete is only requested again once the timeout has passed
+	require.NoError(t, remove())
+	require.NoError(t, remove())
+	assert.Len(t, server.Requests(http.MethodDelete, itemPath), 1)
+
+	for range recoveryServicesVaultMaxRequests - 1 {
+		now = now.Add(recoveryServicesVaultRequestTimeout)
+		require.NoError(t, remove())
+	}
+	assert.Len(t, server.Requests(http.MethodDelete, itemPath), recoveryServicesVaultMaxRequests)
+
+	now = now.Add(recoveryServicesVaultRequestTimeout)
+	assert.ErrorContains(t, remove(), "had no effect after it was requested 3 times")
+	assert.Len(t, server.Requests(http.MethodDelete, itemPath), recoveryServicesVaultMaxRequests)
+}
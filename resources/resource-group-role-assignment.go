@@ -0,0 +1,131 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const ResourceGroupRoleAssignmentResource = "ResourceGroupRoleAssignment"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ResourceGroupRoleAssignmentResource,
+		Scope:    azure.ResourceGroupScope,
+		Resource: &ResourceGroupRoleAssignment{},
+		Lister:   &ResourceGroupRoleAssignmentLister{},
+	})
+}
+
+type ResourceGroupRoleAssignment struct {
+	*BaseResource `property:",inline"`
+
+	client *armauthorization.RoleAssignmentsClient
+
+	ID               *string `property:"-"`
+	Name             *string `description:"The name of the role assignment."`
+	RoleName         *string `description:"The name of the role that is assigned."`
+	RoleDefinitionID *string `description:"The ID of the role definition that is assigned."`
+	PrincipalID      *string `description:"The ID of the principal the role is assigned to."`
+	PrincipalName    *string `description:"The display name of the principal, unknown if it does not exist."`
+	PrincipalType    *string `description:"The type of the principal (user, group, service_principal, foreign_group, device or unknown)."`
+	Orphaned         bool    `description:"Whether the user, group or service principal no longer exists in the directory."`
+	scope            *string
+}
+
+func (r *ResourceGroupRoleAssignment) Remove(ctx context.Context) error {
+	_, err := r.client.Delete(ctx, *r.scope, *r.Name, nil)
+	return err
+}
+
+func (r *ResourceGroupRoleAssignment) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ResourceGroupRoleAssignment) String() string {
+	return fmt.Sprintf("%s -> %s", ptr.ToString(r.PrincipalName), ptr.ToString(r.RoleName))
+}
+
+type ResourceGroupRoleAssignmentLister struct{}
+
+func (l ResourceGroupRoleAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", ResourceGroupRoleAssignmentResource).
+		WithField("s", opts.SubscriptionID).
+		WithField("rg", opts.ResourceGroup)
+
+	resources := make([]resource.Resource, 0)
+
+	client, err := newRoleAssignmentsClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rgScope := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", opts.SubscriptionID, opts.ResourceGroup)
+
+	log.Trace("attempting to list role assignments")
+
+	var assignments []*armauthorization.RoleAssignment
+
+	pager := client.NewListForResourceGroupPager(opts.ResourceGroup,
+		&armauthorization.RoleAssignmentsClientListForResourceGroupOptions{
+			Filter: ptr.String("atScope()"),
+		})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range page.Value {
+			// Note: atScope() also returns the assignments inherited from the subscription and management groups,
+			// those are handled by the scanners of the scope they are assigned at.
+			if !strings.EqualFold(ptr.ToString(t.Properties.Scope), rgScope) {
+				continue
+			}
+
+			assignments = append(assignments, t)
+		}
+	}
+
+	resolved, err := resolveRoleAssignments(ctx, opts.GetDirectory(), assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resolved {
+		resources = append(resources, &ResourceGroupRoleAssignment{
+			BaseResource: &BaseResource{
+				Region:         ptr.String("global"),
+				ResourceGroup:  &opts.ResourceGroup,
+				SubscriptionID: &opts.SubscriptionID,
+			},
+			client:           client,
+			scope:            t.Properties.Scope,
+			ID:               t.ID,
+			Name:             t.Name,
+			RoleName:         t.RoleName,
+			RoleDefinitionID: t.RoleDefinitionID,
+			PrincipalID:      t.Properties.PrincipalID,
+			PrincipalName:    ptr.String(t.Principal.Name),
+			PrincipalType:    t.PrincipalType,
+			Orphaned:         t.Orphaned,
+		})
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
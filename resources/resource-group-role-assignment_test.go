@@ -0,0 +1,79 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func roleAssignmentServer(t *testing.T) *azuretest.Server {
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet,
+		"/subscriptions/"+azuretest.SubscriptionID+"/resourceGroups/rg-test/providers/Microsoft.Authorization/roleAssignments",
+		http.StatusOK, "resource-group-role-assignment-list.json")
+	server.Respond(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+
+		"/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7",
+		http.StatusOK, map[string]interface{}{
+			"properties": map[string]string{"roleName": "Reader"},
+		})
+	server.Respond(http.MethodPost, "/v1.0/directoryObjects/getByIds", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{{
+			"@odata.type": "#microsoft.graph.user",
+			"id":          "11111111-1111-1111-1111-111111111111",
+			"displayName": "Test User",
+		}},
+	})
+
+	return server
+}
+
+func TestResourceGroupRoleAssignmentListAndRemove(t *testing.T) {
+	server := roleAssignmentServer(t)
+	deletePath := "/subscriptions/" + azuretest.SubscriptionID +
+		"/resourceGroups/rg-test/providers/Microsoft.Authorization/roleAssignments/bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"
+	server.Respond(http.MethodDelete, deletePath, http.StatusOK, nil)
+
+	lister := ResourceGroupRoleAssignmentLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-test",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	assignment := resources[0].(*ResourceGroupRoleAssignment)
+	assert.Equal(t, "unknown -> Reader", assignment.String())
+	assert.Equal(t, "true", assignment.Properties().Get("Orphaned"))
+	assert.Equal(t, "service_principal", assignment.Properties().Get("PrincipalType"))
+	assert.Equal(t, "rg-test", assignment.Properties().Get("ResourceGroup"))
+	assert.Equal(t, "acdd72a7-3385-48ef-bd42-f606fba81ae7", assignment.Properties().Get("RoleDefinitionID"))
+
+	assert.NoError(t, assignment.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, deletePath), 1)
+}
+
+func TestResourceRoleAssignmentList(t *testing.T) {
+	server := roleAssignmentServer(t)
+
+	lister := ResourceRoleAssignmentLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-test",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	assignment := resources[0].(*ResourceRoleAssignment)
+	assert.Equal(t, "Test User -> Reader (sttest)", assignment.String())
+	assert.False(t, assignment.Orphaned)
+	assert.Equal(t, "user", assignment.Properties().Get("PrincipalType"))
+	assert.True(t, azure.IsOptIn(ResourceRoleAssignmentResource))
+}
@@ -69,7 +69,8 @@ func (l ResourceGroupLister) List(ctx context.Context, o interface{}) ([]resourc
 
 	log := logrus.WithField("r", ResourceGroupResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armresources.NewResourceGroupsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armresources.NewResourceGroupsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestResourceGroupListAndRemove(t *testing.T) {
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet,
+		"/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups", http.StatusOK, "resource-group-list.json")
+	server.Respond(http.MethodDelete,
+		"/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups/rg-dev", http.StatusOK, nil)
+
+	lister := ResourceGroupLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	rg := resources[0].(*ResourceGroup)
+	assert.Equal(t, "rg-dev", rg.String())
+	assert.Equal(t, "eastus", rg.GetRegion())
+	assert.Equal(t, azuretest.SubscriptionID, rg.GetSubscriptionID())
+	assert.Equal(t, "dev", rg.Properties().Get("tag:env"))
+	assert.Equal(t, ptr.String("westeurope"), resources[1].(*ResourceGroup).Region)
+
+	assert.NoError(t, rg.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete,
+		"/subscriptions/"+azuretest.SubscriptionID+"/resourcegroups/rg-dev"), 1)
+}
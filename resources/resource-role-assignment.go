@@ -0,0 +1,135 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const ResourceRoleAssignmentResource = "ResourceRoleAssignment"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     ResourceRoleAssignmentResource,
+		Scope:    azure.ResourceGroupScope,
+		Resource: &ResourceRoleAssignment{},
+		Lister:   &ResourceRoleAssignmentLister{},
+	})
+
+	// Note: the assignments of a resource are removed together with the resource, they only have to be removed on
+	// their own when the resource is kept, so it has to be explicitly included
+	azure.RegisterOptIn(ResourceRoleAssignmentResource)
+}
+
+type ResourceRoleAssignment struct {
+	*BaseResource `property:",inline"`
+
+	client *armauthorization.RoleAssignmentsClient
+
+	ID               *string `property:"-"`
+	Name             *string `description:"The name of the role assignment."`
+	Scope            *string `description:"The ID of the resource the role is assigned at."`
+	RoleName         *string `description:"The name of the role that is assigned."`
+	RoleDefinitionID *string `description:"The ID of the role definition that is assigned."`
+	PrincipalID      *string `description:"The ID of the principal the role is assigned to."`
+	PrincipalName    *string `description:"The display name of the principal, unknown if it does not exist."`
+	PrincipalType    *string `description:"The type of the principal (user, group, service_principal, foreign_group, device or unknown)."`
+	Orphaned         bool    `description:"Whether the user, group or service principal no longer exists in the directory."`
+}
+
+func (r *ResourceRoleAssignment) Remove(ctx context.Context) error {
+	_, err := r.client.Delete(ctx, *r.Scope, *r.Name, nil)
+	return err
+}
+
+func (r *ResourceRoleAssignment) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *ResourceRoleAssignment) String() string {
+	scopeParts := strings.Split(ptr.ToString(r.Scope), "/")
+
+	return fmt.Sprintf("%s -> %s (%s)",
+		ptr.ToString(r.PrincipalName), ptr.ToString(r.RoleName), scopeParts[len(scopeParts)-1])
+}
+
+type ResourceRoleAssignmentLister struct{}
+
+func (l ResourceRoleAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	log := logrus.WithField("r", ResourceRoleAssignmentResource).
+		WithField("s", opts.SubscriptionID).
+		WithField("rg", opts.ResourceGroup)
+
+	resources := make([]resource.Resource, 0)
+
+	client, err := newRoleAssignmentsClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcePrefix := strings.ToLower(
+		fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/", opts.SubscriptionID, opts.ResourceGroup))
+
+	log.Trace("attempting to list role assignments")
+
+	var assignments []*armauthorization.RoleAssignment
+
+	// Note: without a filter the assignments of all resources within the resource group are returned as well
+	pager := client.NewListForResourceGroupPager(opts.ResourceGroup, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range page.Value {
+			if !strings.HasPrefix(strings.ToLower(ptr.ToString(t.Properties.Scope)), resourcePrefix) {
+				continue
+			}
+
+			assignments = append(assignments, t)
+		}
+	}
+
+	resolved, err := resolveRoleAssignments(ctx, opts.GetDirectory(), assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resolved {
+		resources = append(resources, &ResourceRoleAssignment{
+			BaseResource: &BaseResource{
+				Region:         ptr.String("global"),
+				ResourceGroup:  &opts.ResourceGroup,
+				SubscriptionID: &opts.SubscriptionID,
+			},
+			client:           client,
+			ID:               t.ID,
+			Name:             t.Name,
+			Scope:            t.Properties.Scope,
+			RoleName:         t.RoleName,
+			RoleDefinitionID: t.RoleDefinitionID,
+			PrincipalID:      t.Properties.PrincipalID,
+			PrincipalName:    ptr.String(t.Principal.Name),
+			PrincipalType:    t.PrincipalType,
+			Orphaned:         t.Orphaned,
+		})
+	}
+
+	log.Trace("done")
+
+	return resources, nil
+}
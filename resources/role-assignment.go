@@ -0,0 +1,107 @@
+package resources
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gotidy/ptr"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+// roleAssignmentPrincipalTypes are the principal types of role assignments, those that are looked up in the directory
+// of the tenant are the user, group and service principal, the others such as foreign groups of Azure Lighthouse live
+// in another directory
+var roleAssignmentPrincipalTypes = map[armauthorization.PrincipalType]string{
+	armauthorization.PrincipalTypeUser:             azure.PrincipalTypeUser,
+	armauthorization.PrincipalTypeGroup:            azure.PrincipalTypeGroup,
+	armauthorization.PrincipalTypeServicePrincipal: azure.PrincipalTypeServicePrincipal,
+	armauthorization.PrincipalTypeForeignGroup:     "foreign_group",
+	armauthorization.PrincipalTypeDevice:           "device",
+}
+
+// resolvedRoleAssignment is a role assignment with the name of its role and its principal
+type resolvedRoleAssignment struct {
+	*armauthorization.RoleAssignment
+
+	RoleName         *string
+	RoleDefinitionID *string
+	Principal        *azure.Principal
+
+	// PrincipalType is the type of the principal in the directory, or the type of the assignment when the principal
+	// was not found in the directory
+	PrincipalType *string
+
+	// Orphaned is true when the principal is of a type of the directory of the tenant and was not found in it
+	Orphaned bool
+}
+
+// newRoleAssignmentsClient creates a role assignments client for the subscription of the lister
+func newRoleAssignmentsClient(opts *azure.ListerOpts) (*armauthorization.RoleAssignmentsClient, error) {
+	return armauthorization.NewRoleAssignmentsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+}
+
+// resolveRoleAssignments resolves the role names and the principals of the role assignments with the directory of
+// the run, the principals of all assignments are looked up at once.
+func resolveRoleAssignments(ctx context.Context, directory *azure.DirectoryResolver,
+	assignments []*armauthorization.RoleAssignment) ([]*resolvedRoleAssignment, error) {
+	principalIDs := make([]string, 0, len(assignments))
+	for _, t := range assignments {
+		principalIDs = append(principalIDs, ptr.ToString(t.Properties.PrincipalID))
+	}
+
+	principals, err := directory.Principals(ctx, principalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]*resolvedRoleAssignment, 0, len(assignments))
+	for _, t := range assignments {
+		roleName, err := directory.RoleName(ctx, ptr.ToString(t.Properties.RoleDefinitionID))
+		if err != nil {
+			return nil, err
+		}
+
+		principal := principals[ptr.ToString(t.Properties.PrincipalID)]
+
+		assignment := &resolvedRoleAssignment{
+			RoleAssignment:   t,
+			RoleName:         roleName,
+			RoleDefinitionID: ptr.String(azure.RoleDefinitionGUID(ptr.ToString(t.Properties.RoleDefinitionID))),
+			Principal:        principal,
+			PrincipalType:    ptr.String(principal.Type),
+		}
+
+		if !principal.Exists {
+			assignment.PrincipalType, assignment.Orphaned = assignmentPrincipalType(t.Properties.PrincipalType)
+		}
+
+		resolved = append(resolved, assignment)
+	}
+
+	return resolved, nil
+}
+
+// assignmentPrincipalType returns the principal type of a role assignment whose principal was not found in the
+// directory, and whether the assignment is orphaned because its principal should have been found.
+func assignmentPrincipalType(principalType *armauthorization.PrincipalType) (*string, bool) {
+	if principalType == nil {
+		return ptr.String(azure.PrincipalTypeUnknown), false
+	}
+
+	name, ok := roleAssignmentPrincipalTypes[*principalType]
+	if !ok {
+		name = strings.ToLower(string(*principalType))
+	}
+
+	switch *principalType {
+	case armauthorization.PrincipalTypeUser, armauthorization.PrincipalTypeGroup,
+		armauthorization.PrincipalTypeServicePrincipal:
+		return ptr.String(name), true
+	}
+
+	return ptr.String(name), false
+}
@@ -78,7 +78,7 @@ func (l SecurityAlertsLister) List(ctx context.Context, o interface{}) ([]resour
 
 	locationRe := regexp.MustCompile(SecurityAlertLocation)
 
-	client, err := armsecurity.NewAlertsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armsecurity.NewAlertsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +107,8 @@ func (l SecurityAlertsLister) List(ctx context.Context, o interface{}) ([]resour
 
 			resources = append(resources, &SecurityAlert{
 				BaseResource: &BaseResource{
-					Region: ptr.String(matches[1]),
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         ptr.String(matches[1]),
 				},
 				client:      client,
 				ID:          ptr.ToString(entity.ID),
@@ -69,7 +69,8 @@ func (l SecurityAssessmentLister) List(ctx context.Context, o interface{}) ([]re
 
 	log.Trace("creating client")
 
-	clientFactory, err := armsecurity.NewClientFactory(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	clientFactory, err := armsecurity.NewClientFactory(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +98,8 @@ func (l SecurityAssessmentLister) List(ctx context.Context, o interface{}) ([]re
 			parts := strings.Split(to.String(v.ID), "/providers/Microsoft.Security")
 			resources = append(resources, &SecurityAssessment{
 				BaseResource: &BaseResource{
-					Region: ptr.String("global"),
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         ptr.String("global"),
 				},
 				client:     client,
 				ResourceID: ptr.String(parts[0]),
@@ -87,7 +87,7 @@ func (l SecurityPricingLister) List(ctx context.Context, o interface{}) ([]resou
 
 	log.Trace("creating client")
 
-	client, err := armsecurity.NewPricingsClient(opts.Authorizers.IdentityCreds, nil)
+	client, err := armsecurity.NewPricingsClient(opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -110,7 +110,8 @@ func (l SecurityPricingLister) List(ctx context.Context, o interface{}) ([]resou
 
 		resources = append(resources, &SecurityPricing{
 			BaseResource: &BaseResource{
-				Region: ptr.String("global"),
+				SubscriptionID: &opts.SubscriptionID,
+				Region:         ptr.String("global"),
 			},
 			client:         client,
 			subscriptionID: opts.SubscriptionID,
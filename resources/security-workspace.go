@@ -60,7 +60,8 @@ func (l SecurityWorkspaceLister) List(ctx context.Context, o interface{}) ([]res
 
 	log.Trace("creating client")
 
-	client, err := armsecurity.NewWorkspaceSettingsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armsecurity.NewWorkspaceSettingsClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +85,8 @@ func (l SecurityWorkspaceLister) List(ctx context.Context, o interface{}) ([]res
 
 			resources = append(resources, &SecurityWorkspace{
 				BaseResource: &BaseResource{
-					Region: ptr.String("global"),
+					SubscriptionID: &opts.SubscriptionID,
+					Region:         ptr.String("global"),
 				},
 				client: client,
 				Name:   entity.Name,
@@ -85,6 +85,7 @@ func (l ServicePrincipalsLister) List(ctx context.Context, o interface{}) ([]res
 	client := msgraph.NewServicePrincipalsClient()
 	client.BaseClient.Authorizer = opts.Authorizers.MicrosoftGraph
 	client.BaseClient.DisableRetries = true
+	opts.Authorizers.ConfigureGraphClient(&client.BaseClient)
 
 	log.Trace("attempting to list service principals")
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
@@ -27,6 +28,8 @@ func init() {
 			VirtualMachineResource,
 		},
 	})
+
+	azure.RegisterARMType("Microsoft.Compute/snapshots", ComputeSnapshotResource)
 }
 
 type ComputeSnapshot struct {
@@ -63,7 +66,7 @@ func (l ComputeSnapshotLister) List(ctx context.Context, o interface{}) ([]resou
 
 	log := logrus.WithField("r", ComputeSnapshotResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armcompute.NewSnapshotsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armcompute.NewSnapshotsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -86,11 +89,11 @@ func (l ComputeSnapshotLister) List(ctx context.Context, o interface{}) ([]resou
 			}
 
 			resources = append(resources, &ComputeSnapshot{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client:       client,
 				Name:         entity.Name,
 				Tags:         entity.Tags,
@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &SSHPublicKey{},
 		Lister:   &SSHPublicKeyLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Compute/sshPublicKeys", SSHPublicKeyResource)
 }
 
 type SSHPublicKey struct {
@@ -55,7 +59,7 @@ func (l SSHPublicKeyLister) List(ctx context.Context, o interface{}) ([]resource
 
 	log := logrus.WithField("r", SSHPublicKeyResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armcompute.NewSSHPublicKeysClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armcompute.NewSSHPublicKeysClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -73,11 +77,11 @@ func (l SSHPublicKeyLister) List(ctx context.Context, o interface{}) ([]resource
 
 		for _, entity := range page.Value {
 			resources = append(resources, &SSHPublicKey{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         &opts.Region,
 					SubscriptionID: &opts.SubscriptionID,
 					ResourceGroup:  azure.GetResourceGroupFromID(*entity.ID),
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
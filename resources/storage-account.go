@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
@@ -26,6 +28,8 @@ func init() {
 			VirtualMachineResource,
 		},
 	})
+
+	azure.RegisterARMType("Microsoft.Storage/storageAccounts", StorageAccountResource)
 }
 
 type StorageAccount struct {
@@ -58,7 +62,7 @@ func (l StorageAccountLister) List(ctx context.Context, o interface{}) ([]resour
 
 	log := logrus.WithField("r", StorageAccountResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armstorage.NewAccountsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armstorage.NewAccountsClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -76,11 +80,11 @@ func (l StorageAccountLister) List(ctx context.Context, o interface{}) ([]resour
 
 		for _, entity := range page.Value {
 			resources = append(resources, &StorageAccount{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -0,0 +1,112 @@
+package resources
+
+import (
+	"context"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+)
+
+const SubscriptionManagementLockResource = "SubscriptionManagementLock"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:     SubscriptionManagementLockResource,
+		Scope:    azure.SubscriptionScope,
+		Resource: &SubscriptionManagementLock{},
+		Lister:   &SubscriptionManagementLockLister{},
+	})
+}
+
+type SubscriptionManagementLock struct {
+	*BaseResource `property:",inline"`
+
+	client    *armlocks.ManagementLocksClient
+	ID        *string `property:"-"`
+	Name      *string
+	LockLevel string
+	Scope     string `description:"The scope the lock is applied to, the subscription."`
+}
+
+func (r *SubscriptionManagementLock) Remove(ctx context.Context) error {
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+
+	_, err := r.client.DeleteAtSubscriptionLevel(ctx, *r.Name, nil)
+	return err
+}
+
+func (r *SubscriptionManagementLock) Properties() types.Properties {
+	return types.NewPropertiesFromStruct(r)
+}
+
+func (r *SubscriptionManagementLock) String() string {
+	return *r.Name
+}
+
+type SubscriptionManagementLockLister struct{}
+
+func (l SubscriptionManagementLockLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
+	opts := o.(*azure.ListerOpts)
+
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(30*time.Second))
+	defer cancel()
+
+	log := logrus.WithField("r", SubscriptionManagementLockResource).WithField("s", opts.SubscriptionID)
+
+	resources := make([]resource.Resource, 0)
+
+	client, err := armlocks.NewManagementLocksClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
+	if err != nil {
+		return resources, err
+	}
+
+	log.Trace("attempting to list resources")
+
+	pager := client.NewListAtSubscriptionLevelPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lock := range page.Value {
+			// Note: the locks of resource groups and resources are listed as well, they are handled by the
+			// ManagementLock resource
+			scope := azure.LockScope(ptr.ToString(lock.ID))
+			if azure.LockScopeLevel(scope) != "Subscription" {
+				continue
+			}
+
+			var lockLevel string
+			if lock.Properties != nil && lock.Properties.Level != nil {
+				lockLevel = string(*lock.Properties.Level)
+			}
+
+			resources = append(resources, &SubscriptionManagementLock{
+				BaseResource: (&BaseResource{
+					Region:         ptr.String("global"),
+					SubscriptionID: &opts.SubscriptionID,
+				}).WithSystemData(lock.SystemData),
+				client:    client,
+				ID:        lock.ID,
+				Name:      lock.Name,
+				LockLevel: lockLevel,
+				Scope:     scope,
+			})
+		}
+	}
+
+	log.Trace("done listing")
+
+	return resources, nil
+}
@@ -3,17 +3,11 @@ package resources
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
-	"github.com/hashicorp/go-azure-sdk/sdk/odata"
-	"github.com/manicminer/hamilton/msgraph"
-
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -29,10 +23,7 @@ func init() {
 		Name:     SubscriptionRoleAssignmentResource,
 		Scope:    azure.SubscriptionScope,
 		Resource: &SubscriptionRoleAssignment{},
-		Lister: &SubscriptionRoleAssignmentLister{
-			roleNameCache:      make(map[*string]*string),
-			principalNameCache: make(map[*string]*string),
-		},
+		Lister:   &SubscriptionRoleAssignmentLister{},
 	})
 }
 
@@ -49,6 +40,7 @@ type SubscriptionRoleAssignment struct {
 	PrincipalID      *string
 	PrincipalName    *string
 	PrincipalType    *string
+	Orphaned         bool `description:"Whether the user, group or service principal no longer exists in the directory."`
 	scope            *string
 	subscriptionID   *string
 }
@@ -74,121 +66,58 @@ func (r *SubscriptionRoleAssignment) String() string {
 	return fmt.Sprintf("%s -> %s", *r.PrincipalName, *r.RoleName)
 }
 
-type SubscriptionRoleAssignmentLister struct {
-	roleNameCache      map[*string]*string
-	principalNameCache map[*string]*string
-}
+type SubscriptionRoleAssignmentLister struct{}
 
-func (l *SubscriptionRoleAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) { //nolint:gocyclo,funlen
+func (l *SubscriptionRoleAssignmentLister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
 	opts := o.(*azure.ListerOpts)
 	var resources []resource.Resource
 
 	log := logrus.WithField("r", SubscriptionRoleAssignmentResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armauthorization.NewRoleAssignmentsClient(
-		opts.SubscriptionID, opts.Authorizers.IdentityCreds, &arm.ClientOptions{
-			ClientOptions: azcore.ClientOptions{
-				APIVersion: "2022-04-01",
-			},
-		})
+	client, err := newRoleAssignmentsClient(opts)
 	if err != nil {
 		return resources, nil
 	}
 
-	defClient, err := armauthorization.NewRoleDefinitionsClient(opts.Authorizers.IdentityCreds, nil)
-	if err != nil {
-		return resources, nil
-	}
-
-	userClient := msgraph.NewUsersClient()
-	userClient.BaseClient.Authorizer = opts.Authorizers.Graph
-	userClient.BaseClient.DisableRetries = true
-
-	groupClient := msgraph.NewGroupsClient()
-	groupClient.BaseClient.Authorizer = opts.Authorizers.MicrosoftGraph
-	groupClient.BaseClient.DisableRetries = true
-
-	spClient := msgraph.NewServicePrincipalsClient()
-	spClient.BaseClient.Authorizer = opts.Authorizers.MicrosoftGraph
-	spClient.BaseClient.DisableRetries = true
-
 	log.Debug("listing subscription role assignments")
-	pager := client.NewListPager(&armauthorization.RoleAssignmentsClientListOptions{Filter: ptr.String("atScope()")})
+	pager := client.NewListForSubscriptionPager(&armauthorization.RoleAssignmentsClientListForSubscriptionOptions{
+		Filter: ptr.String("atScope()"),
+	})
 
+	var assignments []*armauthorization.RoleAssignment
 	for pager.More() {
 		nextResult, err := pager.NextPage(ctx)
 		if err != nil {
 			return resources, nil
 		}
-		for _, t := range nextResult.Value {
-			rel, defErr := defClient.GetByID(ctx, *t.Properties.RoleDefinitionID, nil)
-			if defErr != nil {
-				return nil, defErr
-			}
-
-			if _, ok := l.roleNameCache[t.Properties.RoleDefinitionID]; !ok {
-				l.roleNameCache[t.Properties.RoleDefinitionID] = rel.Properties.RoleName
-			}
-
-			principalName := ptr.String("unknown")
-			principalType := ptr.String("unknown")
-
-			if v, ok := l.principalNameCache[t.Properties.PrincipalID]; !ok {
-				u, _, err := userClient.Get(ctx, *t.Properties.PrincipalID, odata.Query{})
-				if err != nil && !strings.Contains(err.Error(), "ResourceNotFound") {
-					return nil, err
-				}
-				if u != nil {
-					log.Debug("found user")
-					principalName = u.DisplayName
-					principalType = ptr.String("user")
-				}
-
-				g, _, err := groupClient.Get(ctx, *t.Properties.PrincipalID, odata.Query{})
-				if err != nil && !strings.Contains(err.Error(), "ResourceNotFound") {
-					return nil, err
-				}
-				if g != nil {
-					log.Debug("found group")
-					principalName = g.DisplayName
-					principalType = ptr.String("group")
-				}
-
-				sp, _, err := spClient.Get(ctx, *t.Properties.PrincipalID, odata.Query{})
-				if err != nil && !strings.Contains(err.Error(), "ResourceNotFound") {
-					return nil, err
-				}
-				if sp != nil {
-					log.Debug("found service principal")
-					principalName = sp.DisplayName
-					principalType = ptr.String("service_principal")
-				}
-
-				l.principalNameCache[t.Properties.PrincipalID] = principalName
-			} else {
-				principalName = v
-			}
-
-			roleDefinitionIDParts := strings.Split(*t.Properties.RoleDefinitionID, "/")
-			roleDefinitionID := roleDefinitionIDParts[len(roleDefinitionIDParts)-1]
-
-			resources = append(resources, &SubscriptionRoleAssignment{
-				BaseResource: &BaseResource{
-					Region: ptr.String("global"),
-				},
-				client:           client,
-				scope:            t.Properties.Scope,
-				subscriptionID:   ptr.String(opts.SubscriptionID),
-				ID:               t.ID,
-				Name:             t.Name,
-				Type:             t.Type,
-				RoleName:         l.roleNameCache[t.Properties.RoleDefinitionID],
-				RoleDefinitionID: ptr.String(roleDefinitionID),
-				PrincipalID:      t.Properties.PrincipalID,
-				PrincipalName:    principalName,
-				PrincipalType:    principalType,
-			})
-		}
+
+		assignments = append(assignments, nextResult.Value...)
+	}
+
+	resolved, err := resolveRoleAssignments(ctx, opts.GetDirectory(), assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range resolved {
+		resources = append(resources, &SubscriptionRoleAssignment{
+			BaseResource: &BaseResource{
+				SubscriptionID: &opts.SubscriptionID,
+				Region:         ptr.String("global"),
+			},
+			client:           client,
+			scope:            t.Properties.Scope,
+			subscriptionID:   ptr.String(opts.SubscriptionID),
+			ID:               t.ID,
+			Name:             t.Name,
+			Type:             t.Type,
+			RoleName:         t.RoleName,
+			RoleDefinitionID: t.RoleDefinitionID,
+			PrincipalID:      t.Properties.PrincipalID,
+			PrincipalName:    ptr.String(t.Principal.Name),
+			PrincipalType:    t.PrincipalType,
+			Orphaned:         t.Orphaned,
+		})
 	}
 
 	return resources, nil
@@ -0,0 +1,67 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestSubscriptionRoleAssignmentListResolvesPrincipalsInBulk(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID + "/providers/Microsoft.Authorization/roleAssignments"
+	roleDefinitionPath := "/subscriptions/" + azuretest.SubscriptionID +
+		"/providers/Microsoft.Authorization/roleDefinitions/acdd72a7-3385-48ef-bd42-f606fba81ae7"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path, http.StatusOK, "subscription-role-assignment-list.json")
+	server.Respond(http.MethodGet, roleDefinitionPath, http.StatusOK, map[string]interface{}{
+		"properties": map[string]string{"roleName": "Reader"},
+	})
+	server.Respond(http.MethodPost, "/v1.0/directoryObjects/getByIds", http.StatusOK, map[string]interface{}{
+		"value": []map[string]string{{
+			"@odata.type": "#microsoft.graph.user",
+			"id":          "11111111-1111-1111-1111-111111111111",
+			"displayName": "Test User",
+		}},
+	})
+
+	authorizers := server.Authorizers()
+	opts := &azure.ListerOpts{
+		Authorizers:    authorizers,
+		SubscriptionID: azuretest.SubscriptionID,
+		Directory:      azure.NewDirectoryResolver(authorizers),
+	}
+
+	lister := SubscriptionRoleAssignmentLister{}
+	resources, err := lister.List(context.TODO(), opts)
+	assert.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	user := resources[0].(*SubscriptionRoleAssignment)
+	assert.Equal(t, "Test User -> Reader", user.String())
+	assert.Equal(t, "user", user.Properties().Get("PrincipalType"))
+	assert.False(t, user.Orphaned)
+	assert.NoError(t, user.Filter())
+
+	deleted := resources[1].(*SubscriptionRoleAssignment)
+	assert.Equal(t, "unknown -> Reader", deleted.String())
+	assert.Equal(t, "service_principal", deleted.Properties().Get("PrincipalType"))
+	assert.True(t, deleted.Orphaned)
+
+	// Note: a foreign group, e.g. of Azure Lighthouse, lives in another directory and is not orphaned
+	foreign := resources[2].(*SubscriptionRoleAssignment)
+	assert.Equal(t, "foreign_group", foreign.Properties().Get("PrincipalType"))
+	assert.False(t, foreign.Orphaned)
+
+	// Note: listing again, e.g. for another subscription, is served from the directory cache
+	_, err = lister.List(context.TODO(), opts)
+	assert.NoError(t, err)
+
+	assert.Len(t, server.Requests(http.MethodPost, "/v1.0/directoryObjects/getByIds"), 1)
+	assert.Len(t, server.Requests(http.MethodGet, roleDefinitionPath), 1)
+}
@@ -25,6 +25,8 @@ func init() {
 		Resource: &VirtualMachine{},
 		Lister:   &VirtualMachineLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Compute/virtualMachines", VirtualMachineResource)
 }
 
 type VirtualMachine struct {
@@ -65,7 +67,8 @@ func (l VirtualMachineLister) List(ctx context.Context, o interface{}) ([]resour
 
 	log := logrus.WithField("r", VirtualMachineResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armcompute.NewVirtualMachinesClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armcompute.NewVirtualMachinesClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -96,11 +99,11 @@ func (l VirtualMachineLister) List(ctx context.Context, o interface{}) ([]resour
 			}
 
 			resources = append(resources, &VirtualMachine{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client:       client,
 				Name:         entity.Name,
 				Tags:         entity.Tags,
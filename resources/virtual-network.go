@@ -3,6 +3,8 @@ package resources
 import (
 	"context"
 
+	"github.com/gotidy/ptr"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
@@ -23,6 +25,8 @@ func init() {
 		Resource: &VirtualNetwork{},
 		Lister:   &VirtualNetworkLister{},
 	})
+
+	azure.RegisterARMType("Microsoft.Network/virtualNetworks", VirtualNetworkResource)
 }
 
 type VirtualNetworkLister struct{}
@@ -32,7 +36,8 @@ func (l VirtualNetworkLister) List(ctx context.Context, o interface{}) ([]resour
 
 	log := logrus.WithField("r", VirtualNetworkResource).WithField("s", opts.SubscriptionID)
 
-	client, err := armnetwork.NewVirtualNetworksClient(opts.SubscriptionID, opts.Authorizers.IdentityCreds, nil)
+	client, err := armnetwork.NewVirtualNetworksClient(
+		opts.SubscriptionID, opts.Authorizers.IdentityCreds, opts.Authorizers.ARMClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +55,11 @@ func (l VirtualNetworkLister) List(ctx context.Context, o interface{}) ([]resour
 
 		for _, entity := range page.Value {
 			resources = append(resources, &VirtualNetwork{
-				BaseResource: &BaseResource{
+				BaseResource: (&BaseResource{
 					Region:         entity.Location,
 					ResourceGroup:  &opts.ResourceGroup,
 					SubscriptionID: &opts.SubscriptionID,
-				},
+				}).WithResourceTimes(opts.GetResourceTimes(ctx, ptr.ToString(entity.ID))),
 				client: client,
 				Name:   entity.Name,
 				Tags:   entity.Tags,
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ekristen/azure-nuke/pkg/azure"
+	"github.com/ekristen/azure-nuke/pkg/azuretest"
+)
+
+func TestVirtualNetworkListAndRemove(t *testing.T) {
+	path := "/subscriptions/" + azuretest.SubscriptionID +
+		"/resourceGroups/rg-dev/providers/Microsoft.Network/virtualNetworks"
+
+	server := azuretest.NewServer(t)
+	server.RespondWithFixture(http.MethodGet, path, http.StatusOK, "virtual-network-list.json")
+	server.RespondWithFixture(http.MethodGet, "/subscriptions/"+azuretest.SubscriptionID+"/resources",
+		http.StatusOK, "resource-times-list.json")
+	server.Respond(http.MethodDelete, path+"/vnet-dev", http.StatusOK, nil)
+
+	lister := VirtualNetworkLister{}
+	resources, err := lister.List(context.TODO(), &azure.ListerOpts{
+		Authorizers:    server.Authorizers(),
+		SubscriptionID: azuretest.SubscriptionID,
+		ResourceGroup:  "rg-dev",
+	})
+	assert.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	vnet := resources[0].(*VirtualNetwork)
+	assert.Equal(t, "vnet-dev", vnet.String())
+	assert.Equal(t, "rg-dev", vnet.GetResourceGroup())
+	assert.Equal(t, "team-a", vnet.Properties().Get("tag:owner"))
+	assert.Equal(t, "2024-03-10T12:00:00Z", vnet.Properties().Get("CreatedAt"))
+	assert.Equal(t, "2024-03-11T09:15:00Z", vnet.Properties().Get("LastModifiedAt"))
+
+	assert.NoError(t, vnet.Remove(context.TODO()))
+	assert.Len(t, server.Requests(http.MethodDelete, path+"/vnet-dev"), 1)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ekristen/libnuke/pkg/docs"
 	"github.com/ekristen/libnuke/pkg/registry"
 
+	"github.com/ekristen/azure-nuke/pkg/azure"
 	_ "github.com/ekristen/azure-nuke/resources"
 )
 
@@ -32,6 +33,9 @@ func main() {
 
 		markdown += fmt.Sprintf("- **Type:** `%s`\n", reg.Name)
 		markdown += fmt.Sprintf("- **Scope:** %s\n", reg.Scope)
+		if azure.IsOptIn(reg.Name) {
+			markdown += "- **Opt-In:** only scanned when explicitly included\n"
+		}
 
 		markdown += "\n"
 